@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -12,9 +13,18 @@ import (
 
 var (
 	configFilePath string
+	concurrency    int
+	maxFailures    int
+	reportPath     string
+	checkpointPath string
+	resume         bool
+	since          string
+	stateFilePath  string
+	useAuditLog    bool
+	bulkOutput     outputFlags
 	bulkImportCmd  = &cobra.Command{
 		Use:   "bulk-import",
-		Short: "A command that imports all repositories from a given organization",
+		Short: "A command that imports all repositories of the configured organizations, or of $OWNER",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			fmt.Println("Config file path: ", configFilePath)
 
@@ -23,21 +33,135 @@ var (
 				return fmt.Errorf("failed to decode configuration: %w", err)
 			}
 
+			if cmd.Flags().Changed("concurrency") {
+				cfg.Concurrency = &concurrency
+			}
+			if cmd.Flags().Changed("max-failures") {
+				cfg.MaxFailures = &maxFailures
+			}
+			if cmd.Flags().Changed("output-dir") {
+				cfg.OutputDir = &bulkOutput.outputDir
+			}
+			if cmd.Flags().Changed("dump-dir") {
+				cfg.DumpDir = &bulkOutput.dumpDir
+			}
+			if cmd.Flags().Changed("no-dumps") {
+				cfg.NoDumps = &bulkOutput.noDumps
+			}
+			repoDumpDir := *cfg.DumpDir
+			if *cfg.NoDumps {
+				repoDumpDir = ""
+			}
+
 			if err := cfg.Validate(); err != nil {
 				return fmt.Errorf("failed to validate configuration: %w", err)
 			}
 
-			repos, err := github.ImportRepos(*cfg)
+			orgs, err := cfg.ResolveOrganizations(os.Getenv("OWNER"))
 			if err != nil {
-				return fmt.Errorf("failed to import repositories: %w", err)
+				return err
+			}
+
+			runStartedAt := time.Now()
+			var sinceTime time.Time
+			if since != "" {
+				if sinceTime, err = github.ParseSince(since, runStartedAt); err != nil {
+					return err
+				}
+			} else if stateFilePath != "" {
+				state, err := github.LoadImportState(stateFilePath)
+				if err != nil {
+					return err
+				}
+				if state != nil {
+					sinceTime = state.LastRun
+				}
+			}
+			if !sinceTime.IsZero() {
+				fmt.Printf("importing repositories changed since %s\n", sinceTime.Format(time.RFC3339))
+			}
+
+			// A relative --since is kept as given, so resuming with the same flags picks up the
+			// checkpoint even though the cutoff it resolves to moved on.
+			sinceKey := since
+			if sinceKey == "" && !sinceTime.IsZero() {
+				sinceKey = sinceTime.Format(time.RFC3339Nano)
+			}
+			runHash, err := github.RunHash(configFilePath, os.Getenv("OWNER"), sinceKey)
+			if err != nil {
+				return err
+			}
+
+			checkpoint := github.NewCheckpoint(checkpointPath, runHash)
+			if resume {
+				if checkpoint, err = github.LoadCheckpoint(checkpointPath, runHash); err != nil {
+					return err
+				}
+			}
+
+			// A GitHub App installation only covers its own organization, so without a fixed
+			// installation ID every organization authenticates on its own.
+			perOrgClients := clientConfig.AppID != 0 && clientConfig.AppInstallationID == 0
+
+			var importer *github.Importer
+			report := github.NewImportReport()
+			for _, org := range orgs {
+				if importer == nil || perOrgClients {
+					orgClientConfig := clientConfig.WithEndpointDefaults(*cfg)
+					if perOrgClients {
+						orgClientConfig.AppOwner = org.Name
+					}
+					if importer, err = newImporter(orgClientConfig); err != nil {
+						return err
+					}
+					importer.WithDumpDir(repoDumpDir)
+				}
+
+				_, orgReport, err := importer.ImportRepos(*cfg, github.BulkImportOptions{
+					Owner: org.Name,
+					Skip:  checkpoint.IsCompleted,
+					OnImported: func(repoName string, repo *github.Repository) error {
+						if err := github.WriteRepositoryToYaml(*cfg.OutputDir, repo); err != nil {
+							return fmt.Errorf("failed to handle repository: %w", err)
+						}
+						if err := checkpoint.MarkCompleted(repoName); err != nil {
+							fmt.Printf("failed to update checkpoint: %v\n", err)
+						}
+						return nil
+					},
+					Since:       sinceTime,
+					UseAuditLog: useAuditLog,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to import repositories: %w", err)
+				}
+				report.Merge(orgReport)
 			}
 
-			for _, repo := range repos {
-				if err := github.WriteRepositoryToYaml(repo); err != nil {
-					return fmt.Errorf("failed to handle repository: %w", err)
+			// Failed repositories keep the checkpoint, so a --resume run only retries them,
+			// and the state, so the next incremental run picks them up again.
+			if len(report.Failed) == 0 {
+				if err := checkpoint.Remove(); err != nil {
+					fmt.Printf("%v\n", err)
+				}
+				if stateFilePath != "" {
+					if err := (github.ImportState{LastRun: runStartedAt}).WriteJSONFile(stateFilePath); err != nil {
+						return err
+					}
 				}
 			}
 
+			fmt.Print(report.Summary())
+			if reportPath != "" {
+				if err := report.WriteJSONFile(reportPath); err != nil {
+					return err
+				}
+			}
+
+			if report.ExceedsFailureThreshold(*cfg.MaxFailures) {
+				return fmt.Errorf("%d repositories failed to import, more than the allowed %d", len(report.Failed), *cfg.MaxFailures)
+			}
+
 			return nil
 		},
 	}
@@ -46,6 +170,17 @@ var (
 func init() {
 	rootCmd.AddCommand(bulkImportCmd)
 	bulkImportCmd.Flags().StringVarP(&configFilePath, "config", "c", "./import-config.yaml", "Path to the yaml config file (defaults to ./import-config.yaml)")
+	bulkImportCmd.Flags().IntVar(&maxFailures, "max-failures", 0, "Number of repositories allowed to fail before the command exits non-zero (overrides max_failures in the config file)")
+	bulkImportCmd.Flags().StringVar(&reportPath, "report", "./import-report.json", "Path of the JSON import report, empty to skip writing it")
+	bulkImportCmd.Flags().StringVar(&checkpointPath, "checkpoint", "./import-checkpoint.json", "Path of the checkpoint file recording the repositories imported so far")
+	bulkImportCmd.Flags().BoolVar(&resume, "resume", false, "Skip repositories an interrupted run already imported, unless the config file, $OWNER or the incremental cutoff changed since")
+	bulkImportCmd.Flags().StringVar(&since, "since", "", "Only import repositories updated or pushed to since this RFC 3339 timestamp or duration ago, e.g. 2h")
+	bulkImportCmd.Flags().StringVar(&stateFilePath, "state-file", "", "File remembering the last successful run; when set and --since is not, only repositories changed since that run are imported")
+	bulkImportCmd.Flags().BoolVar(&useAuditLog, "audit-log", false, "With --since or --state-file, also import repositories with audit log events since then (GitHub Enterprise Cloud only)")
+	bulkImportCmd.Flags().StringVar(&bulkOutput.outputDir, "output-dir", github.DefaultOutputDir, "Directory the <owner>/<repo>.yaml configurations are written to (overrides output_dir in the config file)")
+	bulkImportCmd.Flags().StringVar(&bulkOutput.dumpDir, "dump-dir", github.DefaultDumpDir, "Directory the raw API responses are dumped to (overrides dump_dir in the config file)")
+	bulkImportCmd.Flags().BoolVar(&bulkOutput.noDumps, "no-dumps", false, "Do not dump the raw API responses (overrides no_dumps in the config file)")
+	bulkImportCmd.Flags().IntVar(&concurrency, "concurrency", github.DefaultConcurrency, "Number of repositories imported at the same time (overrides concurrency in the config file)")
 }
 
 func DecodeConfiguration(configFilePath string) (*github.Config, error) {
@@ -70,5 +205,30 @@ func DecodeConfiguration(configFilePath string) (*github.Config, error) {
 		cfg.PageSize = &ps
 	}
 
+	if cfg.MaxFailures == nil {
+		mf := 0
+		cfg.MaxFailures = &mf
+	}
+
+	if cfg.Concurrency == nil {
+		c := github.DefaultConcurrency
+		cfg.Concurrency = &c
+	}
+
+	if cfg.OutputDir == nil {
+		od := github.DefaultOutputDir
+		cfg.OutputDir = &od
+	}
+
+	if cfg.DumpDir == nil {
+		dd := github.DefaultDumpDir
+		cfg.DumpDir = &dd
+	}
+
+	if cfg.NoDumps == nil {
+		nd := false
+		cfg.NoDumps = &nd
+	}
+
 	return &cfg, nil
 }
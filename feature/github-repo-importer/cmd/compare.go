@@ -1,32 +1,67 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/gr-oss-devops/github-repo-importer/pkg/compare"
 	"github.com/spf13/cobra"
 )
 
-var compareCmd = &cobra.Command{
-	Use:   "compare [dir1] [dir2]",
-	Short: "Compare command compares two directories and generates a diff",
-	Args:  cobra.ExactArgs(2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		dirA := args[0]
-		dirB := args[1]
-
-		result, err := compare.CompareDirectories(dirA, dirB)
-		if err != nil {
-			return fmt.Errorf("Error comparing directories: %w\n", err)
-		}
-
-		output, _ := json.MarshalIndent(result, "", "  ")
-		fmt.Println(string(output))
-		return nil
-	},
-}
+var (
+	diffMode          bool
+	compareFormat     string
+	compareConfigPath string
+	ignorePaths       []string
+	noDefaultIgnores  bool
+	compareCmd        = &cobra.Command{
+		Use:   "compare [dir1] [dir2]",
+		Short: "Compare command compares two directories and generates a diff",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dirA := args[0]
+			dirB := args[1]
+
+			var opts compare.Options
+			if compareConfigPath != "" {
+				cfg, err := compare.LoadConfig(compareConfigPath)
+				if err != nil {
+					return err
+				}
+				opts.IgnorePaths = cfg.Ignore
+			}
+			if opts.IgnorePaths == nil && noDefaultIgnores {
+				opts.IgnorePaths = []string{}
+			}
+			if len(ignorePaths) > 0 {
+				if opts.IgnorePaths == nil {
+					opts.IgnorePaths = compare.DefaultIgnorePaths
+				}
+				opts.IgnorePaths = append(slices.Clone(opts.IgnorePaths), ignorePaths...)
+			}
+
+			compareDirectories := compare.CompareDirectories
+			if diffMode {
+				compareDirectories = compare.DiffDirectories
+			}
+
+			result, err := compareDirectories(dirA, dirB, opts)
+			if err != nil {
+				return fmt.Errorf("Error comparing directories: %w\n", err)
+			}
+
+			return compare.WriteResult(os.Stdout, result, compareFormat)
+		},
+	}
+)
 
 func init() {
 	rootCmd.AddCommand(compareCmd)
+	compareCmd.Flags().BoolVar(&diffMode, "diff", false, "List the changed fields of every different file, e.g. rulesets[name=main].enforcement")
+	compareCmd.Flags().StringVarP(&compareConfigPath, "config", "c", "", "Path to a compare config file whose ignore list replaces the default one")
+	compareCmd.Flags().StringArrayVar(&ignorePaths, "ignore", nil, "Path to leave out of the comparison, e.g. rulesets[*].id or webhooks[*].url (repeatable, added to the configured ones)")
+	compareCmd.Flags().BoolVar(&noDefaultIgnores, "no-default-ignores", false, "Do not ignore ids (..id) unless a config file or --ignore says so")
+	compareCmd.Flags().StringVar(&compareFormat, "format", compare.FormatJSON, fmt.Sprintf("Output format, one of %s", strings.Join(compare.Formats, ", ")))
 }
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var customPropertiesCmd = &cobra.Command{
+	Use:   "custom-properties [org]",
+	Short: "Custom properties command prints the custom property schema of an organization",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		org := args[0]
+
+		importer, err := newImporter(clientConfig)
+		if err != nil {
+			return err
+		}
+
+		schema, err := importer.ImportCustomPropertySchema(org)
+		if err != nil {
+			return fmt.Errorf("failed to import custom property schema: %w", err)
+		}
+
+		output, err := yaml.Marshal(map[string]interface{}{"custom_properties": schema})
+		if err != nil {
+			return fmt.Errorf("failed to marshal custom property schema to YAML: %w", err)
+		}
+
+		fmt.Print(string(output))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(customPropertiesCmd)
+}
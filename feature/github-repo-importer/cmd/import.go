@@ -2,32 +2,68 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/gr-oss-devops/github-repo-importer/pkg/github"
 )
 
-var importCmd = &cobra.Command{
-	Use:   "import [owner/repo]",
-	Short: "Import command reads all repository details and creates a configuration yaml file",
-	Args:  cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		repository := args[0]
+var (
+	excludeDefaultLabels bool
+	fromDumps            string
+	importOutput         outputFlags
+	importCmd            = &cobra.Command{
+		Use:   "import [owner/repo]",
+		Short: "Import command reads all repository details and creates a configuration yaml file",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repository := args[0]
+			opts := github.ImportOptions{ExcludeDefaultLabels: excludeDefaultLabels}
 
-		repo, err := github.ImportRepo(repository)
-		if err != nil {
-			return fmt.Errorf("failed to import repo: %w", err)
-		}
+			var repo *github.Repository
+			if fromDumps != "" {
+				// Offline: no client, so no token is needed.
+				var err error
+				if repo, err = github.ImportRepoFromDumps(fromDumps, repository, opts); err != nil {
+					return fmt.Errorf("failed to import repo from dumps: %w", err)
+				}
+			} else {
+				repoClientConfig := clientConfig
+				// Without an installation ID or owner, use the installation of the repository owner.
+				if repoClientConfig.AppID != 0 && repoClientConfig.AppInstallationID == 0 && repoClientConfig.AppOwner == "" {
+					repoClientConfig.AppOwner, _, _ = strings.Cut(repository, "/")
+				}
 
-		if err := github.WriteRepositoryToYaml(repo); err != nil {
-			return fmt.Errorf("failed to handle repository: %w", err)
-		}
+				importer, err := newImporter(repoClientConfig)
+				if err != nil {
+					return err
+				}
+				if importOutput.noDumps {
+					importer.WithDumpDir("")
+				} else {
+					importer.WithDumpDir(importOutput.dumpDir)
+				}
 
-		return nil
-	},
-}
+				if repo, err = importer.ImportRepo(repository, opts); err != nil {
+					return fmt.Errorf("failed to import repo: %w", err)
+				}
+			}
+
+			if err := github.WriteRepositoryToYaml(importOutput.outputDir, repo); err != nil {
+				return fmt.Errorf("failed to handle repository: %w", err)
+			}
+
+			return nil
+		},
+	}
+)
 
 func init() {
 	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&fromDumps, "from-dumps", "", "Rebuild the repository from the dumps directory of an earlier import (e.g. ./dumps) instead of calling GitHub")
+	importCmd.Flags().StringVar(&importOutput.outputDir, "output-dir", github.DefaultOutputDir, "Directory the <owner>/<repo>.yaml configuration is written to")
+	importCmd.Flags().StringVar(&importOutput.dumpDir, "dump-dir", github.DefaultDumpDir, "Directory the raw API responses are dumped to")
+	importCmd.Flags().BoolVar(&importOutput.noDumps, "no-dumps", false, "Do not dump the raw API responses")
+	importCmd.Flags().BoolVar(&excludeDefaultLabels, "exclude-default-labels", false, "Leave GitHub's unmodified default issue labels out of issue_labels")
 }
@@ -1,14 +1,43 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/github"
 )
 
-var rootCmd = &cobra.Command{
-	Use:   "importer",
-	Short: "A CLI tool to fetch GitHub repository details, branch protection rules & rulesets",
+// outputFlags hold the --output-dir, --dump-dir and --no-dumps flags. import and
+// bulk-import each bind their own, with their own defaults and help texts.
+type outputFlags struct {
+	outputDir string
+	dumpDir   string
+	noDumps   bool
+}
+
+var (
+	clientConfig github.ClientConfig
+	rootCmd      = &cobra.Command{
+		Use:   "importer",
+		Short: "A CLI tool to fetch GitHub repository details, branch protection rules & rulesets",
+	}
+)
+
+func init() {
+	envConfig, err := github.ClientConfigFromEnv()
+	if err != nil {
+		fmt.Printf("ignoring GitHub App environment variables: %v\n", err)
+	}
+
+	rootCmd.PersistentFlags().Int64Var(&clientConfig.AppID, "app-id", envConfig.AppID, "GitHub App id to authenticate as (defaults to $GITHUB_APP_ID)")
+	rootCmd.PersistentFlags().Int64Var(&clientConfig.AppInstallationID, "app-installation-id", envConfig.AppInstallationID, "GitHub App installation id (defaults to $GITHUB_APP_INSTALLATION_ID, looked up from --app-owner when unset)")
+	rootCmd.PersistentFlags().StringVar(&clientConfig.AppOwner, "app-owner", envConfig.AppOwner, "Organization or user whose app installation is used (defaults to $OWNER, then to the owner of the imported repository)")
+	rootCmd.PersistentFlags().StringVar(&clientConfig.AppPrivateKeyFile, "app-private-key-file", envConfig.AppPrivateKeyFile, "Path to the GitHub App private key (defaults to $GITHUB_APP_PRIVATE_KEY_FILE)")
+	rootCmd.PersistentFlags().StringVar(&clientConfig.BaseURL, "api-url", envConfig.BaseURL, "REST API base URL of a GitHub Enterprise Server (defaults to $IMPORTER_API_URL)")
+	rootCmd.PersistentFlags().StringVar(&clientConfig.UploadURL, "upload-url", envConfig.UploadURL, "Upload URL of a GitHub Enterprise Server (defaults to $IMPORTER_UPLOAD_URL, then the API URL)")
+	rootCmd.PersistentFlags().StringVar(&clientConfig.GraphQLURL, "graphql-url", envConfig.GraphQLURL, "GraphQL URL of a GitHub Enterprise Server (defaults to $IMPORTER_GRAPHQL_URL, then derived from the API URL)")
 }
 
 func Execute() {
@@ -17,3 +46,13 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// newImporter authenticates against GitHub. Only commands that talk to the API call it,
+// so offline commands such as compare work without a token.
+func newImporter(clientConfig github.ClientConfig) (*github.Importer, error) {
+	v3client, v4client, err := github.CreateGitHubClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub client: %w", err)
+	}
+	return github.NewImporter(v3client, v4client), nil
+}
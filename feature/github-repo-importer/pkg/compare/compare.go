@@ -18,17 +18,24 @@ type CompareResult struct {
 	Identical []string `json:"identical"`
 	Different []string `json:"different"`
 	OnlyInB   []string `json:"only_in_b"`
+	// Diffs lists the field level changes of the different files, see DiffDirectories.
+	Diffs []FileDiff `json:"diffs,omitempty"`
 }
 
 // CompareDirectories compares two directories containing YAML files.
 // It returns a CompareResult struct containing the comparison results.
 // The comparison is based on the normalized content of the YAML files and hashes.
-func CompareDirectories(dirA, dirB string) (CompareResult, error) {
-	filesA, err := collectYamlHashes(dirA)
+func CompareDirectories(dirA, dirB string, opts Options) (CompareResult, error) {
+	ignores, err := opts.ignorePaths()
 	if err != nil {
 		return CompareResult{}, err
 	}
-	filesB, err := collectYamlHashes(dirB)
+
+	filesA, err := collectYamlHashes(dirA, ignores)
+	if err != nil {
+		return CompareResult{}, err
+	}
+	filesB, err := collectYamlHashes(dirB, ignores)
 	if err != nil {
 		return CompareResult{}, err
 	}
@@ -52,10 +59,15 @@ func CompareDirectories(dirA, dirB string) (CompareResult, error) {
 		}
 	}
 
+	sort.Strings(result.OnlyInA)
+	sort.Strings(result.Identical)
+	sort.Strings(result.Different)
+	sort.Strings(result.OnlyInB)
+
 	return result, nil
 }
 
-func collectYamlHashes(root string) (map[string]string, error) {
+func collectYamlHashes(root string, ignores [][]pathSegment) (map[string]string, error) {
 	hashes := make(map[string]string)
 
 	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
@@ -75,7 +87,7 @@ func collectYamlHashes(root string) (map[string]string, error) {
 			return err
 		}
 
-		hash, err := hashNormalizedYamlFile(path)
+		hash, err := hashNormalizedYamlFile(path, ignores)
 		if err != nil {
 			return fmt.Errorf("error hashing %s: %w", path, err)
 		}
@@ -86,27 +98,16 @@ func collectYamlHashes(root string) (map[string]string, error) {
 	return hashes, err
 }
 
-func hashNormalizedYamlFile(path string) (string, error) {
-	data, err := os.ReadFile(path)
+func hashNormalizedYamlFile(path string, ignores [][]pathSegment) (string, error) {
+	node, err := loadNormalizedYamlFile(path, ignores)
 	if err != nil {
 		return "", err
 	}
 
-	var node yaml.Node
-	if err := yaml.Unmarshal(data, &node); err != nil {
-		return "", fmt.Errorf("Can not unmarshal file to yaml: %w\n", err)
-	}
-
-	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
-		root := node.Content[0]
-		removeKey(root, "id")
-		sortMappingNode(root)
-	}
-
 	var buf bytes.Buffer
 	enc := yaml.NewEncoder(&buf)
 	enc.SetIndent(2)
-	if err := enc.Encode(&node); err != nil {
+	if err := enc.Encode(node); err != nil {
 		return "", err
 	}
 	enc.Close()
@@ -115,23 +116,28 @@ func hashNormalizedYamlFile(path string) (string, error) {
 	return fmt.Sprintf("%x", sum), nil
 }
 
-func removeKey(node *yaml.Node, target string) {
-	if node.Kind == yaml.MappingNode {
-		newContent := []*yaml.Node{}
-		for i := 0; i < len(node.Content); i += 2 {
-			k := node.Content[i]
-			v := node.Content[i+1]
-			if k.Value != target {
-				removeKey(v, target)
-				newContent = append(newContent, k, v)
-			}
-		}
-		node.Content = newContent
-	} else if node.Kind == yaml.SequenceNode {
-		for _, elem := range node.Content {
-			removeKey(elem, target)
+// loadNormalizedYamlFile parses a YAML file and strips what differs between otherwise
+// identical configurations: the ignored paths and the order of mapping keys and set-like lists.
+func loadNormalizedYamlFile(path string, ignores [][]pathSegment) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("Can not unmarshal file to yaml: %w\n", err)
+	}
+
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		root := node.Content[0]
+		for _, ignore := range ignores {
+			removePath(root, ignore)
 		}
+		sortMappingNode(root)
 	}
+
+	return &node, nil
 }
 
 func sortMappingNode(node *yaml.Node) {
@@ -159,6 +165,9 @@ func sortMappingNode(node *yaml.Node) {
 		node.Content = append(node.Content, kv.Key, kv.Value)
 		sortMappingNode(kv.Value)
 		sortSequenceIfNeeded(kv.Value)
+		if list, ok := findUnorderedList(kv.Key.Value); ok {
+			sortUnorderedSequence(kv.Value, list.key)
+		}
 	}
 }
 
@@ -174,3 +183,72 @@ func sortSequenceIfNeeded(node *yaml.Node) {
 		}
 	}
 }
+
+// unorderedList describes a list whose order carries no meaning, such as topics or the
+// rulesets GitHub returns in whatever order. Lists of mappings are matched by their natural key.
+type unorderedList struct {
+	field string
+	key   string
+}
+
+// unorderedLists are matched against the name of the field holding the list, using shell
+// globs.
+var unorderedLists = []unorderedList{
+	{field: "topics"},
+	{field: "*_collaborators"},
+	{field: "*_teams"},
+	{field: "rulesets", key: "name"},
+	{field: "branch_protections_v4", key: "pattern"},
+	{field: "required_check", key: "context"},
+	{field: "contexts"},
+	{field: "issue_labels", key: "name"},
+	{field: "environments", key: "name"},
+	{field: "webhooks", key: "url"},
+	// Deploy key titles need not be unique, the public keys are.
+	{field: "deploy_keys", key: "key"},
+}
+
+func findUnorderedList(field string) (unorderedList, bool) {
+	for _, list := range unorderedLists {
+		if matched, _ := filepath.Match(list.field, field); matched {
+			return list, true
+		}
+	}
+	return unorderedList{}, false
+}
+
+// sortUnorderedSequence orders a set-like sequence by its natural key, or by value for
+// scalars and elements without the key. The elements must be normalized already.
+func sortUnorderedSequence(node *yaml.Node, key string) {
+	if node.Kind != yaml.SequenceNode {
+		return
+	}
+
+	sortKeys := make(map[*yaml.Node]string, len(node.Content))
+	for _, item := range node.Content {
+		sortKeys[item] = sequenceSortKey(item, key)
+	}
+
+	sort.SliceStable(node.Content, func(i, j int) bool {
+		return sortKeys[node.Content[i]] < sortKeys[node.Content[j]]
+	})
+}
+
+func sequenceSortKey(node *yaml.Node, key string) string {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return node.Value
+	case yaml.MappingNode:
+		for i := 0; key != "" && i < len(node.Content); i += 2 {
+			if node.Content[i].Value == key && node.Content[i+1].Kind == yaml.ScalarNode {
+				return node.Content[i+1].Value
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHashingYamlFiles(t *testing.T) {
@@ -37,12 +38,28 @@ func TestHashingYamlFiles(t *testing.T) {
 			pathOfFresh:    "testdata/existing/existing4.yaml",
 			wantEqual:      true,
 		},
+		{
+			name:           "two identical yaml files - set-like lists in a different order",
+			pathOfImported: "testdata/imported/imported5.yaml",
+			pathOfFresh:    "testdata/existing/existing5.yaml",
+			wantEqual:      true,
+		},
+		{
+			name:           "two different yaml files",
+			pathOfImported: "testdata/imported/imported5.yaml",
+			pathOfFresh:    "testdata/imported/imported4.yaml",
+			wantEqual:      false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			hashOfImported, err := hashNormalizedYamlFile(tt.pathOfImported)
-			hashOfFresh, err := hashNormalizedYamlFile(tt.pathOfFresh)
+			ignores, err := Options{}.ignorePaths()
+			require.NoError(t, err)
+
+			hashOfImported, err := hashNormalizedYamlFile(tt.pathOfImported, ignores)
+			require.NoError(t, err)
+			hashOfFresh, err := hashNormalizedYamlFile(tt.pathOfFresh, ignores)
 
 			assert.NoError(t, err)
 			if tt.wantEqual {
@@ -0,0 +1,258 @@
+package compare
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// ChangeKind tells how a path differs between the two files.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a single difference between two YAML documents. Path addresses the value the
+// way the configuration is written, e.g. rulesets[name=main].rules.pull_request.
+type Change struct {
+	Path string      `json:"path"`
+	Kind ChangeKind  `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// FileDiff lists the changes of a file present in both directories.
+type FileDiff struct {
+	File    string   `json:"file"`
+	Changes []Change `json:"changes"`
+}
+
+// identityKeys identify the elements of a list of mappings, so that elements are matched
+// by what they are rather than by their position.
+var identityKeys = []string{"name"}
+
+// DiffDirectories compares two directories like CompareDirectories and additionally lists
+// the field level changes of every file that differs.
+func DiffDirectories(dirA, dirB string, opts Options) (CompareResult, error) {
+	result, err := CompareDirectories(dirA, dirB, opts)
+	if err != nil {
+		return CompareResult{}, err
+	}
+
+	for _, relPath := range result.Different {
+		changes, err := DiffYamlFiles(filepath.Join(dirA, relPath), filepath.Join(dirB, relPath), opts)
+		if err != nil {
+			return CompareResult{}, fmt.Errorf("error diffing %s: %w", relPath, err)
+		}
+		result.Diffs = append(result.Diffs, FileDiff{File: relPath, Changes: changes})
+	}
+
+	return result, nil
+}
+
+// DiffYamlFiles lists the changes from the YAML file at pathA to the one at pathB, after
+// the same normalization the hash comparison applies.
+func DiffYamlFiles(pathA, pathB string, opts Options) ([]Change, error) {
+	ignores, err := opts.ignorePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := loadNormalizedYamlValue(pathA, ignores)
+	if err != nil {
+		return nil, err
+	}
+	b, err := loadNormalizedYamlValue(pathB, ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	diffValues("", "", a, b, &changes)
+	return changes, nil
+}
+
+func loadNormalizedYamlValue(path string, ignores [][]pathSegment) (interface{}, error) {
+	node, err := loadNormalizedYamlFile(path, ignores)
+	if err != nil {
+		return nil, err
+	}
+
+	var value interface{}
+	if node.Kind == 0 {
+		return value, nil
+	}
+	if err := node.Decode(&value); err != nil {
+		return nil, fmt.Errorf("Can not decode yaml of %s: %w", path, err)
+	}
+	return value, nil
+}
+
+// diffValues diffs the values of a field, where field is the mapping key holding them, or
+// "" for list elements and the document itself.
+func diffValues(path, field string, a, b interface{}, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			diffMappings(path, av, bv, changes)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			diffSequences(path, field, av, bv, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, Change{Path: path, Kind: ChangeChanged, Old: a, New: b})
+	}
+}
+
+func diffMappings(path string, a, b map[string]interface{}, changes *[]Change) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, exists := a[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := key
+		if path != "" {
+			keyPath = path + "." + key
+		}
+
+		valueA, inA := a[key]
+		valueB, inB := b[key]
+		switch {
+		case !inB:
+			*changes = append(*changes, Change{Path: keyPath, Kind: ChangeRemoved, Old: valueA})
+		case !inA:
+			*changes = append(*changes, Change{Path: keyPath, Kind: ChangeAdded, New: valueB})
+		default:
+			diffValues(keyPath, key, valueA, valueB, changes)
+		}
+	}
+}
+
+func diffSequences(path, field string, a, b []interface{}, changes *[]Change) {
+	list, unordered := findUnorderedList(field)
+	if unordered && list.key != "" && identifiesElements(list.key, a, b) {
+		diffKeyedSequences(path, list.key, a, b, changes)
+		return
+	}
+	if key := identityKey(a, b); key != "" {
+		diffKeyedSequences(path, key, a, b, changes)
+		return
+	}
+	if unordered {
+		diffSets(path, a, b, changes)
+		return
+	}
+
+	for i := 0; i < max(len(a), len(b)); i++ {
+		elemPath := fmt.Sprintf("%s[%d]", path, i)
+		switch {
+		case i >= len(b):
+			*changes = append(*changes, Change{Path: elemPath, Kind: ChangeRemoved, Old: a[i]})
+		case i >= len(a):
+			*changes = append(*changes, Change{Path: elemPath, Kind: ChangeAdded, New: b[i]})
+		default:
+			diffValues(elemPath, "", a[i], b[i], changes)
+		}
+	}
+}
+
+// diffKeyedSequences matches the elements of both lists by their key. Elements keep the
+// order of the first list, followed by the elements only the second list has.
+func diffKeyedSequences(path, key string, a, b []interface{}, changes *[]Change) {
+	elemPath := func(id string) string {
+		return fmt.Sprintf("%s[%s=%s]", path, key, id)
+	}
+
+	elemsB := make(map[string]interface{}, len(b))
+	for _, elem := range b {
+		elemsB[elementID(elem, key)] = elem
+	}
+
+	idsA := make(map[string]bool, len(a))
+	for _, elem := range a {
+		id := elementID(elem, key)
+		idsA[id] = true
+		if elemB, exists := elemsB[id]; exists {
+			diffValues(elemPath(id), "", elem, elemB, changes)
+		} else {
+			*changes = append(*changes, Change{Path: elemPath(id), Kind: ChangeRemoved, Old: elem})
+		}
+	}
+
+	for _, elem := range b {
+		if id := elementID(elem, key); !idsA[id] {
+			*changes = append(*changes, Change{Path: elemPath(id), Kind: ChangeAdded, New: elem})
+		}
+	}
+}
+
+// diffSets reports the elements only one of two unordered lists has, under the path of the
+// list itself, since their position carries no meaning.
+func diffSets(path string, a, b []interface{}, changes *[]Change) {
+	for _, elem := range a {
+		if !slices.ContainsFunc(b, func(other interface{}) bool { return reflect.DeepEqual(elem, other) }) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeRemoved, Old: elem})
+		}
+	}
+	for _, elem := range b {
+		if !slices.ContainsFunc(a, func(other interface{}) bool { return reflect.DeepEqual(elem, other) }) {
+			*changes = append(*changes, Change{Path: path, Kind: ChangeAdded, New: elem})
+		}
+	}
+}
+
+// identityKey returns the first of identityKeys that every element of both lists has a
+// scalar value for, unique within each list, or "" when there is none.
+func identityKey(lists ...[]interface{}) string {
+	for _, key := range identityKeys {
+		if identifiesElements(key, lists...) {
+			return key
+		}
+	}
+	return ""
+}
+
+func identifiesElements(key string, lists ...[]interface{}) bool {
+	for _, list := range lists {
+		seen := make(map[string]bool, len(list))
+		for _, elem := range list {
+			id := elementID(elem, key)
+			if id == "" || seen[id] {
+				return false
+			}
+			seen[id] = true
+		}
+	}
+	return true
+}
+
+// elementID returns the scalar value of key in a mapping element, or "".
+func elementID(elem interface{}, key string) string {
+	mapping, ok := elem.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch value := mapping[key].(type) {
+	case nil, map[string]interface{}, []interface{}:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,205 @@
+package compare
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffYamlFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		yamlA    string
+		yamlB    string
+		expected []Change
+	}{
+		{
+			name:     "identical apart from ids and key order",
+			yamlA:    "rulesets:\n  - id: 1\n    name: main\n    target: branch\nvisibility: public\n",
+			yamlB:    "visibility: public\nrulesets:\n  - target: branch\n    name: main\n",
+			expected: []Change{},
+		},
+		{
+			name:  "changed nested field of a named list element",
+			yamlA: "rulesets:\n  - name: main\n    rules:\n      pull_request:\n        required_approving_review_count: 1\n",
+			yamlB: "rulesets:\n  - name: main\n    rules:\n      pull_request:\n        required_approving_review_count: 2\n",
+			expected: []Change{
+				{Path: "rulesets[name=main].rules.pull_request.required_approving_review_count", Kind: ChangeChanged, Old: 1, New: 2},
+			},
+		},
+		{
+			name:  "named list elements added and removed",
+			yamlA: "environments:\n  - name: prod\n  - name: staging\n",
+			yamlB: "environments:\n  - name: dev\n  - name: prod\n",
+			expected: []Change{
+				{Path: "environments[name=staging]", Kind: ChangeRemoved, Old: map[string]interface{}{"name": "staging"}},
+				{Path: "environments[name=dev]", Kind: ChangeAdded, New: map[string]interface{}{"name": "dev"}},
+			},
+		},
+		{
+			name:  "ordered list elements without name are compared by position",
+			yamlA: "conditions:\n  ref_name:\n    include: [main, release]\n",
+			yamlB: "conditions:\n  ref_name:\n    include: [main, develop, release]\n",
+			expected: []Change{
+				{Path: "conditions.ref_name.include[1]", Kind: ChangeChanged, Old: "release", New: "develop"},
+				{Path: "conditions.ref_name.include[2]", Kind: ChangeAdded, New: "release"},
+			},
+		},
+		{
+			name:  "unordered lists are compared as sets",
+			yamlA: "topics: [go, api]\nadmin_teams: [platform, security]\n",
+			yamlB: "topics: [api, golang, terraform]\nadmin_teams: [security, platform]\n",
+			expected: []Change{
+				{Path: "topics", Kind: ChangeRemoved, Old: "go"},
+				{Path: "topics", Kind: ChangeAdded, New: "golang"},
+				{Path: "topics", Kind: ChangeAdded, New: "terraform"},
+			},
+		},
+		{
+			name: "unordered lists of mappings are matched by their natural key",
+			yamlA: `branch_protections_v4:
+  - pattern: main
+    required_status_checks:
+      contexts: [lint, test]
+  - pattern: release/*
+rulesets:
+  - name: main
+    rules:
+      required_status_checks:
+        required_check:
+          - context: test
+            integration_id: 1
+          - context: lint
+`,
+			yamlB: `rulesets:
+  - name: main
+    rules:
+      required_status_checks:
+        required_check:
+          - context: lint
+          - context: test
+            integration_id: 2
+branch_protections_v4:
+  - pattern: release/*
+  - pattern: main
+    required_status_checks:
+      contexts: [test, lint]
+`,
+			expected: []Change{
+				{Path: "rulesets[name=main].rules.required_status_checks.required_check[context=test].integration_id", Kind: ChangeChanged, Old: 1, New: 2},
+			},
+		},
+		{
+			name: "repository settings lists are matched by their natural key",
+			yamlA: `issue_labels:
+  - name: bug
+    color: d73a4a
+  - name: docs
+    color: 0075ca
+webhooks:
+  - url: https://ci.example.com
+    events: [push]
+  - url: https://chat.example.com
+    events: [issues]
+deploy_keys:
+  - title: deploy
+    key: ssh-ed25519 AAAA1
+  - title: deploy
+    key: ssh-ed25519 AAAA2
+`,
+			yamlB: `deploy_keys:
+  - title: deploy
+    key: ssh-ed25519 AAAA2
+  - title: deploy
+    key: ssh-ed25519 AAAA1
+webhooks:
+  - url: https://chat.example.com
+    events: [issues]
+  - url: https://ci.example.com
+    events: [push, pull_request]
+issue_labels:
+  - name: docs
+    color: 0075ca
+  - name: bug
+    color: d73a4a
+`,
+			expected: []Change{
+				{Path: "webhooks[url=https://ci.example.com].events[1]", Kind: ChangeAdded, New: "pull_request"},
+			},
+		},
+		{
+			name:  "fields added and removed",
+			yamlA: "description: api\nhas_wiki: true\n",
+			yamlB: "has_wiki: true\nhomepage_url: https://example.com\n",
+			expected: []Change{
+				{Path: "description", Kind: ChangeRemoved, Old: "api"},
+				{Path: "homepage_url", Kind: ChangeAdded, New: "https://example.com"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pathA := filepath.Join(dir, "a.yaml")
+			pathB := filepath.Join(dir, "b.yaml")
+			require.NoError(t, os.WriteFile(pathA, []byte(tt.yamlA), 0o644))
+			require.NoError(t, os.WriteFile(pathB, []byte(tt.yamlB), 0o644))
+
+			changes, err := DiffYamlFiles(pathA, pathB, Options{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	result := CompareResult{
+		OnlyInB:   []string{"acme/web.yaml"},
+		Identical: []string{"acme/docs.yaml"},
+		Different: []string{"acme/api.yaml"},
+		Diffs: []FileDiff{{File: "acme/api.yaml", Changes: []Change{
+			{Path: "rulesets[name=main].enforcement", Kind: ChangeChanged, Old: "active", New: "disabled"},
+			{Path: "topics[1]", Kind: ChangeAdded, New: "go"},
+		}}},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{
+			format: FormatText,
+			expected: `Only in B: acme/web.yaml
+Different: acme/api.yaml
+  rulesets[name=main].enforcement: "active" -> "disabled"
+  topics[1]: added "go"
+1 identical, 1 different, 0 only in A, 1 only in B
+`,
+		},
+		{
+			format: FormatUnified,
+			expected: `Only in B: acme/web.yaml
+--- a/acme/api.yaml
++++ b/acme/api.yaml
+-rulesets[name=main].enforcement: "active"
++rulesets[name=main].enforcement: "disabled"
++topics[1]: "go"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteResult(&buf, result, tt.format))
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+
+	assert.Error(t, WriteResult(&bytes.Buffer{}, result, "html"))
+}
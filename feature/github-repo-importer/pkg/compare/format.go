@@ -0,0 +1,109 @@
+package compare
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Output formats of WriteResult.
+const (
+	FormatJSON    = "json"
+	FormatText    = "text"
+	FormatUnified = "unified"
+)
+
+var Formats = []string{FormatJSON, FormatText, FormatUnified}
+
+// WriteResult writes a comparison result, including its diffs, in one of Formats.
+func WriteResult(w io.Writer, result CompareResult, format string) error {
+	switch format {
+	case FormatJSON:
+		output, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal comparison result: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(output))
+		return err
+	case FormatText:
+		_, err := io.WriteString(w, formatText(result))
+		return err
+	case FormatUnified:
+		_, err := io.WriteString(w, formatUnified(result))
+		return err
+	default:
+		return fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// formatText lists one change per line, e.g. "rulesets[name=main].enforcement: "active" -> "disabled"".
+func formatText(result CompareResult) string {
+	var sb strings.Builder
+	writeOnlyIn(&sb, result)
+
+	diffs := make(map[string]FileDiff, len(result.Diffs))
+	for _, diff := range result.Diffs {
+		diffs[diff.File] = diff
+	}
+
+	for _, relPath := range result.Different {
+		fmt.Fprintf(&sb, "Different: %s\n", relPath)
+		for _, change := range diffs[relPath].Changes {
+			switch change.Kind {
+			case ChangeAdded:
+				fmt.Fprintf(&sb, "  %s: added %s\n", change.Path, formatValue(change.New))
+			case ChangeRemoved:
+				fmt.Fprintf(&sb, "  %s: removed %s\n", change.Path, formatValue(change.Old))
+			default:
+				fmt.Fprintf(&sb, "  %s: %s -> %s\n", change.Path, formatValue(change.Old), formatValue(change.New))
+			}
+		}
+	}
+
+	fmt.Fprintf(&sb, "%d identical, %d different, %d only in A, %d only in B\n",
+		len(result.Identical), len(result.Different), len(result.OnlyInA), len(result.OnlyInB))
+	return sb.String()
+}
+
+// formatUnified mimics a unified diff, with the changed paths in place of lines.
+func formatUnified(result CompareResult) string {
+	var sb strings.Builder
+	writeOnlyIn(&sb, result)
+
+	diffs := make(map[string]FileDiff, len(result.Diffs))
+	for _, diff := range result.Diffs {
+		diffs[diff.File] = diff
+	}
+
+	for _, relPath := range result.Different {
+		fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", relPath, relPath)
+		for _, change := range diffs[relPath].Changes {
+			if change.Kind != ChangeAdded {
+				fmt.Fprintf(&sb, "-%s: %s\n", change.Path, formatValue(change.Old))
+			}
+			if change.Kind != ChangeRemoved {
+				fmt.Fprintf(&sb, "+%s: %s\n", change.Path, formatValue(change.New))
+			}
+		}
+	}
+	return sb.String()
+}
+
+func writeOnlyIn(sb *strings.Builder, result CompareResult) {
+	for _, relPath := range result.OnlyInA {
+		fmt.Fprintf(sb, "Only in A: %s\n", relPath)
+	}
+	for _, relPath := range result.OnlyInB {
+		fmt.Fprintf(sb, "Only in B: %s\n", relPath)
+	}
+}
+
+// formatValue renders values as compact JSON, so strings stay distinguishable from numbers.
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,214 @@
+package compare
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultIgnorePaths is the profile used when none is configured. It leaves out every id,
+// which GitHub assigns and which existing configurations usually lack.
+var DefaultIgnorePaths = []string{"..id"}
+
+// Options tunes the normalization applied before files are compared.
+type Options struct {
+	// IgnorePaths lists the paths left out of the comparison, written like the paths of a
+	// diff: "rulesets[*].id", "webhooks[*].url", "rulesets[name=main].bypass_actors" or
+	// "..id" for an id at any depth. Nil uses DefaultIgnorePaths, an empty list compares
+	// everything.
+	IgnorePaths []string
+}
+
+func (o Options) ignorePaths() ([][]pathSegment, error) {
+	paths := o.IgnorePaths
+	if paths == nil {
+		paths = DefaultIgnorePaths
+	}
+
+	var ignores [][]pathSegment
+	for _, path := range paths {
+		segments, err := parseIgnorePath(path)
+		if err != nil {
+			return nil, err
+		}
+		ignores = append(ignores, segments)
+	}
+	return ignores, nil
+}
+
+// Config is a compare configuration file.
+type Config struct {
+	// Ignore replaces DefaultIgnorePaths when set.
+	Ignore []string `yaml:"ignore"`
+}
+
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read compare config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to decode compare config: %w", err)
+	}
+	return cfg, nil
+}
+
+// pathSegment is one step of an ignore path. It either selects mapping keys, by name or
+// "*", or list elements, by index, "*" or a key=value filter.
+type pathSegment struct {
+	key string
+	// descendant matches the segment at any depth below the previous one.
+	descendant bool
+
+	list bool
+	// index selects a single element, -1 selects all of them unless a filter is set.
+	index       int
+	filterKey   string
+	filterValue string
+}
+
+// parseIgnorePath parses paths such as "$.rulesets[*].id" or "..id". The leading "$" is optional.
+func parseIgnorePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := strings.TrimPrefix(path, "$")
+
+	for rest != "" {
+		descendant := false
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			descendant = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+		case len(segments) > 0 && !strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("invalid ignore path %q: expected . or [ before %q", path, rest)
+		}
+
+		if strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if descendant || end < 0 {
+				return nil, fmt.Errorf("invalid ignore path %q: unexpected %q", path, rest)
+			}
+			segment, err := parseListSelector(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore path %q: %w", path, err)
+			}
+			segments = append(segments, segment)
+			rest = rest[end+1:]
+			continue
+		}
+
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("invalid ignore path %q: empty key", path)
+		}
+		segments = append(segments, pathSegment{key: rest[:end], descendant: descendant})
+		rest = rest[end:]
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.New("ignore path must not be empty")
+	}
+	return segments, nil
+}
+
+func parseListSelector(selector string) (pathSegment, error) {
+	if selector == "*" {
+		return pathSegment{list: true, index: -1}, nil
+	}
+	if key, value, ok := strings.Cut(selector, "="); ok && key != "" {
+		return pathSegment{list: true, index: -1, filterKey: key, filterValue: value}, nil
+	}
+	index, err := strconv.Atoi(selector)
+	if err != nil || index < 0 {
+		return pathSegment{}, fmt.Errorf("invalid list selector [%s], expected [*], [<index>] or [<key>=<value>]", selector)
+	}
+	return pathSegment{list: true, index: index}, nil
+}
+
+func (s pathSegment) matchesKey(key string) bool {
+	return !s.list && (s.key == "*" || s.key == key)
+}
+
+func (s pathSegment) matchesElement(index int, elem *yaml.Node) bool {
+	if !s.list {
+		return false
+	}
+	if s.filterKey != "" {
+		for i := 0; elem.Kind == yaml.MappingNode && i < len(elem.Content); i += 2 {
+			if elem.Content[i].Value == s.filterKey {
+				return elem.Content[i+1].Kind == yaml.ScalarNode && elem.Content[i+1].Value == s.filterValue
+			}
+		}
+		return false
+	}
+	return s.index == -1 || s.index == index
+}
+
+// removePath removes everything below node the path segments match.
+func removePath(node *yaml.Node, segments []pathSegment) {
+	segment := segments[0]
+	if segment.descendant {
+		here := segment
+		here.descendant = false
+		removePath(node, append([]pathSegment{here}, segments[1:]...))
+
+		for _, child := range childNodes(node) {
+			removePath(child, segments)
+		}
+		return
+	}
+
+	last := len(segments) == 1
+	switch node.Kind {
+	case yaml.MappingNode:
+		content := []*yaml.Node{}
+		for i := 0; i < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if segment.matchesKey(k.Value) {
+				if last {
+					continue
+				}
+				removePath(v, segments[1:])
+			}
+			content = append(content, k, v)
+		}
+		node.Content = content
+	case yaml.SequenceNode:
+		content := []*yaml.Node{}
+		for i, elem := range node.Content {
+			if segment.matchesElement(i, elem) {
+				if last {
+					continue
+				}
+				removePath(elem, segments[1:])
+			}
+			content = append(content, elem)
+		}
+		node.Content = content
+	}
+}
+
+func childNodes(node *yaml.Node) []*yaml.Node {
+	switch node.Kind {
+	case yaml.MappingNode:
+		var values []*yaml.Node
+		for i := 1; i < len(node.Content); i += 2 {
+			values = append(values, node.Content[i])
+		}
+		return values
+	case yaml.SequenceNode:
+		return node.Content
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,117 @@
+package compare
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestIgnorePaths(t *testing.T) {
+	input := `rulesets:
+  - id: 1
+    name: main
+    bypass_actors:
+      - actor_id: 5
+  - id: 2
+    name: release
+    bypass_actors:
+      - actor_id: 6
+webhooks:
+  - id: 7
+    url: https://example.com/********
+    events: [push]
+`
+
+	tests := []struct {
+		name        string
+		ignorePaths []string
+		expected    string
+	}{
+		{
+			name: "default profile removes every id",
+			expected: `rulesets:
+  - {name: main, bypass_actors: [{actor_id: 5}]}
+  - {name: release, bypass_actors: [{actor_id: 6}]}
+webhooks:
+  - {url: https://example.com/********, events: [push]}
+`,
+		},
+		{
+			name:        "scoped paths",
+			ignorePaths: []string{"$.rulesets[*].id", "webhooks[*].url"},
+			expected: `rulesets:
+  - {name: main, bypass_actors: [{actor_id: 5}]}
+  - {name: release, bypass_actors: [{actor_id: 6}]}
+webhooks:
+  - {id: 7, events: [push]}
+`,
+		},
+		{
+			name:        "elements selected by key and index",
+			ignorePaths: []string{"rulesets[name=release].bypass_actors", "webhooks[0].events[0]", "rulesets[0].*"},
+			expected: `rulesets:
+  - {id: 2, name: release}
+  - {}
+webhooks:
+  - {id: 7, url: https://example.com/********, events: []}
+`,
+		},
+		{
+			name:        "empty profile compares everything",
+			ignorePaths: []string{},
+			expected:    input,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "repo.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(input), 0o644))
+
+			ignores, err := Options{IgnorePaths: tt.ignorePaths}.ignorePaths()
+			require.NoError(t, err)
+			actual, err := loadNormalizedYamlValue(path, ignores)
+			require.NoError(t, err)
+
+			var expected interface{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.expected), &expected))
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestParseIgnorePathErrors(t *testing.T) {
+	for _, path := range []string{"", "$", "rulesets[", "rulesets[x]", "rulesets[-1]", "rulesets..", "..[*]", "rulesets[*]id"} {
+		t.Run(path, func(t *testing.T) {
+			_, err := parseIgnorePath(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{name: "ignore list", content: "ignore:\n  - rulesets[*].id\n", expected: []string{"rulesets[*].id"}},
+		{name: "empty ignore list", content: "ignore: []\n", expected: []string{}},
+		{name: "no ignore list keeps the default", content: "{}\n", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "compare-config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			cfg, err := LoadConfig(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, cfg.Ignore)
+		})
+	}
+}
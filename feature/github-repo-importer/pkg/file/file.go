@@ -1,26 +1,158 @@
 package file
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 )
 
+const (
+	// DirPerm and FilePerm are the permissions of everything the importer writes.
+	DirPerm  os.FileMode = 0o755
+	FilePerm os.FileMode = 0o644
+)
+
+// WriteFileAtomic writes data to a temporary file next to path and renames it into place,
+// so readers never see a partially written file. Missing parent directories are created.
+func WriteFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, DirPerm); err != nil {
+		return fmt.Errorf("failed to create directory %q: %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %q: %w", path, err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write file %q: %w", path, err)
+	}
+	if err := tmp.Chmod(FilePerm); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to set permissions of %q: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file %q: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace file %q: %w", path, err)
+	}
+	return nil
+}
+
 type DumpManager struct {
 	base string
+	// target is where a staged dump manager moves base to on Commit.
+	target string
 }
 
-func NewDumpManager(base string) (*DumpManager, error) {
-	b := filepath.Join("./dumps", base)
-	if err := os.MkdirAll(b, os.ModePerm); err != nil {
+// NewDumpManager writes dumps below dir/base. An empty dir disables dumping, WriteJSONFile
+// then writes nothing.
+func NewDumpManager(dir, base string) (*DumpManager, error) {
+	if dir == "" {
+		return &DumpManager{}, nil
+	}
+
+	b := filepath.Join(dir, base)
+	if err := os.MkdirAll(b, DirPerm); err != nil {
 		return nil, fmt.Errorf("failed to create base directories: %w", err)
 	}
 
 	return &DumpManager{base: b}, nil
 }
 
+// NewStagedDumpManager writes dumps to a temporary directory next to dir/base. Commit
+// replaces the previous dumps with it, so files an earlier run wrote, such as a deleted
+// ruleset or a page a listing no longer has, never mix with the current ones. An empty
+// dir disables dumping.
+func NewStagedDumpManager(dir, base string) (*DumpManager, error) {
+	if dir == "" {
+		return &DumpManager{}, nil
+	}
+
+	target := filepath.Join(dir, base)
+	if err := os.MkdirAll(filepath.Dir(target), DirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create base directories: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if err := os.Chmod(staging, DirPerm); err != nil {
+		_ = os.RemoveAll(staging)
+		return nil, fmt.Errorf("failed to set permissions of %q: %w", staging, err)
+	}
+
+	return &DumpManager{base: staging, target: target}, nil
+}
+
+// Commit replaces the previous dumps with the staged ones. It does nothing for dump
+// managers that are not staged.
+func (dm *DumpManager) Commit() error {
+	if dm.target == "" {
+		return nil
+	}
+
+	// A directory cannot be renamed over a non-empty one, so the old dumps move aside first.
+	previous, err := os.MkdirTemp(filepath.Dir(dm.target), "."+filepath.Base(dm.target)+".old-*")
+	if err != nil {
+		return fmt.Errorf("failed to replace dumps %q: %w", dm.target, err)
+	}
+	if err := os.Remove(previous); err != nil {
+		return fmt.Errorf("failed to replace dumps %q: %w", dm.target, err)
+	}
+
+	hadPrevious := true
+	if err := os.Rename(dm.target, previous); errors.Is(err, os.ErrNotExist) {
+		hadPrevious = false
+	} else if err != nil {
+		return fmt.Errorf("failed to replace dumps %q: %w", dm.target, err)
+	}
+
+	if err := os.Rename(dm.base, dm.target); err != nil {
+		if hadPrevious {
+			_ = os.Rename(previous, dm.target)
+		}
+		return fmt.Errorf("failed to replace dumps %q: %w", dm.target, err)
+	}
+	dm.target = ""
+
+	if hadPrevious {
+		if err := os.RemoveAll(previous); err != nil {
+			return fmt.Errorf("failed to remove previous dumps %q: %w", previous, err)
+		}
+	}
+	return nil
+}
+
+// Discard drops staged dumps, leaving the previous ones in place.
+func (dm *DumpManager) Discard() error {
+	if dm.target == "" {
+		return nil
+	}
+	dm.target = ""
+
+	if err := os.RemoveAll(dm.base); err != nil {
+		return fmt.Errorf("failed to remove staged dumps %q: %w", dm.base, err)
+	}
+	return nil
+}
+
 func (dm *DumpManager) WriteJSONFile(fileName string, data interface{}) error {
+	if dm.base == "" {
+		return nil
+	}
+
 	filePath := filepath.Join(dm.base, fileName)
 	fmt.Printf("Creating JSON file: %s\n", filePath)
 
@@ -29,9 +161,78 @@ func (dm *DumpManager) WriteJSONFile(fileName string, data interface{}) error {
 		return fmt.Errorf("failed to marshal json: %v", err)
 	}
 
-	if err := os.WriteFile(filePath, jsonData, 0o644); err != nil {
-		return fmt.Errorf("failed to write file %q: %w", filePath, err)
+	return WriteFileAtomic(filePath, jsonData)
+}
+
+// DumpReader reads back the files a DumpManager wrote.
+type DumpReader struct {
+	base string
+}
+
+func NewDumpReader(base string) (*DumpReader, error) {
+	info, err := os.Stat(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dump directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("dump directory %q is not a directory", base)
 	}
 
-	return nil
+	return &DumpReader{base: base}, nil
+}
+
+// ReadJSONFile decodes a dumped file into data. It reports false, without an error,
+// when the file was never dumped.
+func (dr *DumpReader) ReadJSONFile(fileName string, data interface{}) (bool, error) {
+	filePath := filepath.Join(dr.base, fileName)
+
+	jsonData, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read file %q: %w", filePath, err)
+	}
+
+	if err := json.Unmarshal(jsonData, data); err != nil {
+		return false, fmt.Errorf("failed to unmarshal %q: %w", filePath, err)
+	}
+
+	return true, nil
+}
+
+// Glob returns the names of the dumped files matching pattern. Names ending in a number
+// before the extension, such as hooks-page_2.json or ruleset42.json, are ordered by that number.
+func (dr *DumpReader) Glob(pattern string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dr.base, pattern))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		if n := cmp.Compare(trailingNumber(a), trailingNumber(b)); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+	return names, nil
+}
+
+func trailingNumber(fileName string) int {
+	name := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	start := len(name)
+	for start > 0 && name[start-1] >= '0' && name[start-1] <= '9' {
+		start--
+	}
+
+	n, err := strconv.Atoi(name[start:])
+	if err != nil {
+		return 0
+	}
+	return n
 }
@@ -0,0 +1,73 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "configs", "acme", "api.yaml")
+
+	require.NoError(t, WriteFileAtomic(path, []byte("name: api\n")))
+	require.NoError(t, WriteFileAtomic(path, []byte("name: web\n")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "name: web\n", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, FilePerm, info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files must be left behind")
+}
+
+func TestDumpManagerDisabled(t *testing.T) {
+	// An absolute base catches a disabled manager writing below it anyway.
+	dir := t.TempDir()
+	dumpManager, err := NewDumpManager("", filepath.Join(dir, "acme", "api"))
+	require.NoError(t, err)
+	require.NoError(t, dumpManager.WriteJSONFile("repository.json", map[string]string{"name": "api"}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStagedDumpManager(t *testing.T) {
+	dir := t.TempDir()
+	stage := func(fileName string) *DumpManager {
+		dumpManager, err := NewStagedDumpManager(dir, "acme/api")
+		require.NoError(t, err)
+		require.NoError(t, dumpManager.WriteJSONFile(fileName, map[string]int{"id": 1}))
+		return dumpManager
+	}
+	dumpedFiles := func() []string {
+		entries, err := os.ReadDir(filepath.Join(dir, "acme", "api"))
+		require.NoError(t, err)
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	require.NoError(t, stage("ruleset1.json").Commit())
+	assert.Equal(t, []string{"ruleset1.json"}, dumpedFiles())
+
+	require.NoError(t, stage("ruleset2.json").Commit())
+	assert.Equal(t, []string{"ruleset2.json"}, dumpedFiles(), "dumps of the previous run must be replaced")
+
+	require.NoError(t, stage("ruleset3.json").Discard())
+	assert.Equal(t, []string{"ruleset2.json"}, dumpedFiles(), "discarded dumps must leave the previous ones in place")
+
+	entries, err := os.ReadDir(filepath.Join(dir, "acme"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no staging directories must be left behind")
+}
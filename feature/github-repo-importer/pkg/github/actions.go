@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type Actions struct {
+	Enabled                      *bool                 `yaml:"enabled,omitempty"`
+	AllowedActions               *string               `yaml:"allowed_actions,omitempty"`
+	AllowedActionsConfig         *AllowedActionsConfig `yaml:"allowed_actions_config,omitempty"`
+	DefaultWorkflowPermissions   *string               `yaml:"default_workflow_permissions,omitempty"`
+	CanApprovePullRequestReviews *bool                 `yaml:"can_approve_pull_request_reviews,omitempty"`
+}
+
+type AllowedActionsConfig struct {
+	GithubOwnedAllowed *bool    `yaml:"github_owned_allowed,omitempty"`
+	VerifiedAllowed    *bool    `yaml:"verified_allowed,omitempty"`
+	PatternsAllowed    []string `yaml:"patterns_allowed,omitempty"`
+}
+
+func CollectActions(client *github.Client, owner, repo string, dumpManager *file.DumpManager) (*Actions, error) {
+	permissions, _, err := client.Repositories.GetActionsPermissions(context.Background(), owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actions permissions: %w", err)
+	}
+
+	if err := dumpManager.WriteJSONFile("actions_permissions.json", permissions); err != nil {
+		fmt.Printf("failed to write actions_permissions.json: %v\n", err)
+	}
+
+	var allowed *github.ActionsAllowed
+	if permissions.GetAllowedActions() == AllowedActionsSelected {
+		allowed, _, err = client.Repositories.GetActionsAllowed(context.Background(), owner, repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get allowed actions: %w", err)
+		}
+
+		if err := dumpManager.WriteJSONFile("actions_allowed.json", allowed); err != nil {
+			fmt.Printf("failed to write actions_allowed.json: %v\n", err)
+		}
+	}
+
+	workflowPermissions, _, err := client.Repositories.GetDefaultWorkflowPermissions(context.Background(), owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get default workflow permissions: %w", err)
+	}
+
+	if err := dumpManager.WriteJSONFile("actions_workflow_permissions.json", workflowPermissions); err != nil {
+		fmt.Printf("failed to write actions_workflow_permissions.json: %v\n", err)
+	}
+
+	return resolveActions(permissions, allowed, workflowPermissions), nil
+}
+
+func resolveActions(permissions *github.ActionsPermissionsRepository, allowed *github.ActionsAllowed, workflowPermissions *github.DefaultWorkflowPermissionRepository) *Actions {
+	if permissions == nil {
+		return nil
+	}
+
+	actions := &Actions{
+		Enabled:        permissions.Enabled,
+		AllowedActions: permissions.AllowedActions,
+	}
+
+	if workflowPermissions != nil {
+		actions.DefaultWorkflowPermissions = workflowPermissions.DefaultWorkflowPermissions
+		actions.CanApprovePullRequestReviews = workflowPermissions.CanApprovePullRequestReviews
+	}
+
+	if allowed != nil {
+		actions.AllowedActionsConfig = &AllowedActionsConfig{
+			GithubOwnedAllowed: allowed.GithubOwnedAllowed,
+			VerifiedAllowed:    allowed.VerifiedAllowed,
+			PatternsAllowed:    allowed.PatternsAllowed,
+		}
+	}
+
+	return actions
+}
@@ -0,0 +1,165 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// GitHub rejects app JWTs that live longer than ten minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockDrift backdates iat to tolerate clock skew against GitHub.
+	appJWTClockDrift = time.Minute
+	// installationTokenRefreshMargin renews installation tokens before they expire,
+	// so requests in flight never carry a token that expires mid-call.
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// appJWTSource signs the short-lived JWTs a GitHub App uses to talk to the /app endpoints.
+type appJWTSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+func (s *appJWTSource) Token() (*oauth2.Token, error) {
+	now := s.now()
+	expiry := now.Add(appJWTLifetime)
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return nil, err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iat": now.Add(-appJWTClockDrift).Unix(),
+		"exp": expiry.Unix(),
+		"iss": fmt.Sprintf("%d", s.appID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign app JWT: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: unsigned + "." + base64.RawURLEncoding.EncodeToString(signature),
+		TokenType:   "Bearer",
+		Expiry:      expiry.Add(-appJWTClockDrift),
+	}, nil
+}
+
+// installationTokenSource exchanges the app JWT for installation access tokens.
+type installationTokenSource struct {
+	appClient      *github.Client
+	installationID int64
+}
+
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	token, _, err := s.appClient.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation token for installation %d: %w", s.installationID, err)
+	}
+
+	fmt.Printf("created installation token expiring at %s\n", token.GetExpiresAt())
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt().Add(-installationTokenRefreshMargin),
+	}, nil
+}
+
+// newAppHTTPClient returns an HTTP client authenticated as the given app installation.
+// Installation tokens are renewed transparently, which keeps bulk imports that outlive
+// the one-hour token lifetime running.
+func newAppHTTPClient(ctx context.Context, cfg ClientConfig, newGitHubClient func(*http.Client) (*github.Client, error)) (*http.Client, error) {
+	key, err := readAppPrivateKey(cfg.AppPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtSource := oauth2.ReuseTokenSource(nil, &appJWTSource{appID: cfg.AppID, key: key, now: time.Now})
+	// Installation tokens are refreshed mid-import, so a transient failure creating one must be
+	// retried like any other request instead of failing the repositories imported meanwhile.
+	appHTTPClient := oauth2.NewClient(ctx, jwtSource)
+	appHTTPClient.Transport = newRetryTransport(appHTTPClient.Transport, cfg.retryPolicy())
+	appClient, err := newGitHubClient(appHTTPClient)
+	if err != nil {
+		return nil, err
+	}
+
+	installationID := cfg.AppInstallationID
+	if installationID == 0 {
+		installationID, err = findInstallationID(ctx, appClient, cfg.AppOwner)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ts := oauth2.ReuseTokenSource(nil, &installationTokenSource{appClient: appClient, installationID: installationID})
+	return oauth2.NewClient(ctx, ts), nil
+}
+
+func findInstallationID(ctx context.Context, appClient *github.Client, owner string) (int64, error) {
+	if owner == "" {
+		return 0, errors.New("either an app installation id or an owner to look it up is required")
+	}
+
+	installation, _, err := appClient.Apps.FindOrganizationInstallation(ctx, owner)
+	if err != nil {
+		var userErr error
+		installation, _, userErr = appClient.Apps.FindUserInstallation(ctx, owner)
+		if userErr != nil {
+			return 0, fmt.Errorf("failed to find app installation for %s: %w", owner, errors.Join(err, userErr))
+		}
+	}
+
+	fmt.Printf("using app installation %d of %s\n", installation.GetID(), owner)
+	return installation.GetID(), nil
+}
+
+func readAppPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app private key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("app private key %s is not PEM encoded", path)
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse app private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("app private key is not an RSA key")
+	}
+	return key, nil
+}
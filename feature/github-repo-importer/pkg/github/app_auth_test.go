@@ -0,0 +1,111 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppHTTPClientLooksUpInstallationAndRefreshesTokens(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "app.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+
+	var tokensIssued atomic.Int32
+	var tokenRequestFailed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/my-org/installation":
+			assertValidAppJWT(t, &key.PublicKey, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"id": 42}`))
+		case "/app/installations/42/access_tokens":
+			assertValidAppJWT(t, &key.PublicKey, r.Header.Get("Authorization"))
+			// A refresh hitting a transient server error is retried.
+			if tokensIssued.Load() == 1 && !tokenRequestFailed.Swap(true) {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			n := tokensIssued.Add(1)
+			// Expires within the refresh margin, so every request needs a fresh token.
+			expiresAt := time.Now().Add(installationTokenRefreshMargin - time.Minute).UTC().Format(time.RFC3339)
+			_, _ = fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, expiresAt)
+		case "/repos/my-org/repo":
+			assert.Equal(t, fmt.Sprintf("Bearer ghs_%d", tokensIssued.Load()), r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"name": "repo"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	newGitHubClient := func(hc *http.Client) (*github.Client, error) {
+		client := github.NewClient(hc)
+		client.BaseURL = baseURL
+		return client, nil
+	}
+
+	cfg := ClientConfig{AppID: 123, AppOwner: "my-org", AppPrivateKeyFile: keyFile, RetryPolicy: &RetryPolicy{MaxRetries: 1, MaxWait: time.Millisecond}}
+	httpClient, err := newAppHTTPClient(context.Background(), cfg, newGitHubClient)
+	require.NoError(t, err)
+
+	client, err := newGitHubClient(httpClient)
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Repositories.Get(context.Background(), "my-org", "repo")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), tokensIssued.Load())
+	assert.True(t, tokenRequestFailed.Load())
+}
+
+func assertValidAppJWT(t *testing.T, key *rsa.PublicKey, authorization string) {
+	t.Helper()
+
+	jwt, ok := strings.CutPrefix(authorization, "Bearer ")
+	require.True(t, ok, "authorization header %q is not a bearer token", authorization)
+
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims struct {
+		Iss string `json:"iss"`
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	assert.Equal(t, "123", claims.Iss)
+	assert.LessOrEqual(t, claims.Exp-claims.Iat, int64((10 * time.Minute).Seconds()))
+}
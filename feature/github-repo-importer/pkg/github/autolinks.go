@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// AutolinkReference mirrors the autolink_references module variable, which names
+// the API's url_template field target_url_template.
+type AutolinkReference struct {
+	KeyPrefix         string `yaml:"key_prefix"`
+	TargetURLTemplate string `yaml:"target_url_template"`
+	IsAlphanumeric    *bool  `yaml:"is_alphanumeric,omitempty"`
+}
+
+func CollectAutolinkReferences(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]AutolinkReference, error) {
+	var references []AutolinkReference
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		autolinks, resp, err := client.Repositories.ListAutolinks(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list autolinks: %w", err)
+		}
+
+		filename := fmt.Sprintf("autolinks-page_%d.json", opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, autolinks); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		references = append(references, resolveAutolinkReferences(autolinks)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return references, nil
+}
+
+func resolveAutolinkReferences(autolinks []*github.Autolink) []AutolinkReference {
+	var references []AutolinkReference
+	for _, autolink := range autolinks {
+		if autolink == nil {
+			continue
+		}
+
+		references = append(references, AutolinkReference{
+			KeyPrefix:         autolink.GetKeyPrefix(),
+			TargetURLTemplate: autolink.GetURLTemplate(),
+			IsAlphanumeric:    autolink.IsAlphanumeric,
+		})
+	}
+	return references
+}
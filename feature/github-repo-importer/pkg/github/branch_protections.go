@@ -1,7 +1,14 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/shurcooL/githubv4"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
 )
 
 type BranchProtectionV4 struct {
@@ -40,34 +47,164 @@ type RequiredStatusChecksV4 struct {
 type BranchProtectionRulesGraphQLQuery struct {
 	Repository struct {
 		BranchProtectionRules struct {
-			Nodes []struct {
-				Pattern                        githubv4.String
-				AllowsDeletions                bool
-				AllowsForcePushes              bool
-				BlocksCreations                bool
-				IsAdminEnforced                bool
-				RequiresConversationResolution bool
-				RequiresCommitSignatures       bool
-				RequiresLinearHistory          bool
-				RequiredApprovingReviewCount   *int
-				DismissesStaleReviews          bool
-				RequiresCodeOwnerReviews       bool
-				RestrictsReviewDismissals      bool
-				RequiresStrictStatusChecks     bool
-				RequiresStatusChecks           bool
-				RestrictsPushes                bool
-				RequireLastPushApproval        bool
-				LockBranch                     bool
-				RequiredStatusCheckContexts    []githubv4.String
-				BypassPullRequestAllowances    AllowanceWrapper `graphql:"bypassPullRequestAllowances(first: 100)"`
-				ReviewDismissalAllowances      AllowanceWrapper `graphql:"reviewDismissalAllowances(first: 100)"`
-				BypassForcePushAllowances      AllowanceWrapper `graphql:"bypassForcePushAllowances(first: 100)"`
-				PushAllowances                 AllowanceWrapper `graphql:"pushAllowances(first: 100)"`
-			}
-		} `graphql:"branchProtectionRules(first:100)"`
+			Nodes    []BranchProtectionRuleNode
+			PageInfo PageInfo
+		} `graphql:"branchProtectionRules(first: 100, after: $cursor)"`
+	} `graphql:"repository(owner: $owner, name: $name)"`
+}
+
+type BranchProtectionRuleNode struct {
+	ID                             githubv4.ID
+	Pattern                        githubv4.String
+	AllowsDeletions                bool
+	AllowsForcePushes              bool
+	BlocksCreations                bool
+	IsAdminEnforced                bool
+	RequiresConversationResolution bool
+	RequiresCommitSignatures       bool
+	RequiresLinearHistory          bool
+	RequiredApprovingReviewCount   *int
+	DismissesStaleReviews          bool
+	RequiresCodeOwnerReviews       bool
+	RestrictsReviewDismissals      bool
+	RequiresStrictStatusChecks     bool
+	RequiresStatusChecks           bool
+	RestrictsPushes                bool
+	RequireLastPushApproval        bool
+	LockBranch                     bool
+	RequiredStatusCheckContexts    []githubv4.String
+	BypassPullRequestAllowances    AllowanceWrapper `graphql:"bypassPullRequestAllowances(first: 100)"`
+	ReviewDismissalAllowances      AllowanceWrapper `graphql:"reviewDismissalAllowances(first: 100)"`
+	BypassForcePushAllowances      AllowanceWrapper `graphql:"bypassForcePushAllowances(first: 100)"`
+	PushAllowances                 AllowanceWrapper `graphql:"pushAllowances(first: 100)"`
+}
+
+// legacyBranchProtectionRulesGraphQLQuery leaves out the fields older GitHub Enterprise
+// Server versions do not have: lockBranch, requireLastPushApproval and
+// bypassForcePushAllowances.
+type legacyBranchProtectionRulesGraphQLQuery struct {
+	Repository struct {
+		BranchProtectionRules struct {
+			Nodes    []legacyBranchProtectionRuleNode
+			PageInfo PageInfo
+		} `graphql:"branchProtectionRules(first: 100, after: $cursor)"`
 	} `graphql:"repository(owner: $owner, name: $name)"`
 }
 
+type legacyBranchProtectionRuleNode struct {
+	ID                             githubv4.ID
+	Pattern                        githubv4.String
+	AllowsDeletions                bool
+	AllowsForcePushes              bool
+	BlocksCreations                bool
+	IsAdminEnforced                bool
+	RequiresConversationResolution bool
+	RequiresCommitSignatures       bool
+	RequiresLinearHistory          bool
+	RequiredApprovingReviewCount   *int
+	DismissesStaleReviews          bool
+	RequiresCodeOwnerReviews       bool
+	RestrictsReviewDismissals      bool
+	RequiresStrictStatusChecks     bool
+	RequiresStatusChecks           bool
+	RestrictsPushes                bool
+	RequiredStatusCheckContexts    []githubv4.String
+	BypassPullRequestAllowances    AllowanceWrapper `graphql:"bypassPullRequestAllowances(first: 100)"`
+	ReviewDismissalAllowances      AllowanceWrapper `graphql:"reviewDismissalAllowances(first: 100)"`
+	PushAllowances                 AllowanceWrapper `graphql:"pushAllowances(first: 100)"`
+}
+
+func (q legacyBranchProtectionRulesGraphQLQuery) toQuery() BranchProtectionRulesGraphQLQuery {
+	var query BranchProtectionRulesGraphQLQuery
+	query.Repository.BranchProtectionRules.PageInfo = q.Repository.BranchProtectionRules.PageInfo
+	for _, node := range q.Repository.BranchProtectionRules.Nodes {
+		query.Repository.BranchProtectionRules.Nodes = append(query.Repository.BranchProtectionRules.Nodes, BranchProtectionRuleNode{
+			ID:                             node.ID,
+			Pattern:                        node.Pattern,
+			AllowsDeletions:                node.AllowsDeletions,
+			AllowsForcePushes:              node.AllowsForcePushes,
+			BlocksCreations:                node.BlocksCreations,
+			IsAdminEnforced:                node.IsAdminEnforced,
+			RequiresConversationResolution: node.RequiresConversationResolution,
+			RequiresCommitSignatures:       node.RequiresCommitSignatures,
+			RequiresLinearHistory:          node.RequiresLinearHistory,
+			RequiredApprovingReviewCount:   node.RequiredApprovingReviewCount,
+			DismissesStaleReviews:          node.DismissesStaleReviews,
+			RequiresCodeOwnerReviews:       node.RequiresCodeOwnerReviews,
+			RestrictsReviewDismissals:      node.RestrictsReviewDismissals,
+			RequiresStrictStatusChecks:     node.RequiresStrictStatusChecks,
+			RequiresStatusChecks:           node.RequiresStatusChecks,
+			RestrictsPushes:                node.RestrictsPushes,
+			RequiredStatusCheckContexts:    node.RequiredStatusCheckContexts,
+			BypassPullRequestAllowances:    node.BypassPullRequestAllowances,
+			ReviewDismissalAllowances:      node.ReviewDismissalAllowances,
+			PushAllowances:                 node.PushAllowances,
+		})
+	}
+	return query
+}
+
+// isGraphQLSchemaError reports whether a query asked for a field the schema of the server
+// does not have, as happens on GitHub Enterprise Server versions that predate it.
+func isGraphQLSchemaError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "doesn't exist on type")
+}
+
+// The allowance page queries fetch the remaining pages of a single allowance
+// connection of a rule, once the first page came back with hasNextPage set.
+
+type bypassPullRequestAllowancesPageQuery struct {
+	Node struct {
+		BranchProtectionRule struct {
+			Allowances AllowanceWrapper `graphql:"bypassPullRequestAllowances(first: 100, after: $cursor)"`
+		} `graphql:"... on BranchProtectionRule"`
+	} `graphql:"node(id: $id)"`
+}
+
+type reviewDismissalAllowancesPageQuery struct {
+	Node struct {
+		BranchProtectionRule struct {
+			Allowances AllowanceWrapper `graphql:"reviewDismissalAllowances(first: 100, after: $cursor)"`
+		} `graphql:"... on BranchProtectionRule"`
+	} `graphql:"node(id: $id)"`
+}
+
+type bypassForcePushAllowancesPageQuery struct {
+	Node struct {
+		BranchProtectionRule struct {
+			Allowances AllowanceWrapper `graphql:"bypassForcePushAllowances(first: 100, after: $cursor)"`
+		} `graphql:"... on BranchProtectionRule"`
+	} `graphql:"node(id: $id)"`
+}
+
+type pushAllowancesPageQuery struct {
+	Node struct {
+		BranchProtectionRule struct {
+			Allowances AllowanceWrapper `graphql:"pushAllowances(first: 100, after: $cursor)"`
+		} `graphql:"... on BranchProtectionRule"`
+	} `graphql:"node(id: $id)"`
+}
+
+func (q *bypassPullRequestAllowancesPageQuery) allowances() AllowanceWrapper {
+	return q.Node.BranchProtectionRule.Allowances
+}
+
+func (q *reviewDismissalAllowancesPageQuery) allowances() AllowanceWrapper {
+	return q.Node.BranchProtectionRule.Allowances
+}
+
+func (q *bypassForcePushAllowancesPageQuery) allowances() AllowanceWrapper {
+	return q.Node.BranchProtectionRule.Allowances
+}
+
+func (q *pushAllowancesPageQuery) allowances() AllowanceWrapper {
+	return q.Node.BranchProtectionRule.Allowances
+}
+
+type allowancesPageQuery interface {
+	allowances() AllowanceWrapper
+}
+
 type Actor struct {
 	User UserFragment `graphql:"... on User"`
 	App  AppFragment  `graphql:"... on App"`
@@ -91,5 +228,112 @@ type ActorWrapper struct {
 }
 
 type AllowanceWrapper struct {
-	Nodes []ActorWrapper
+	Nodes    []ActorWrapper
+	PageInfo PageInfo
+}
+
+type PageInfo struct {
+	HasNextPage bool
+	EndCursor   githubv4.String
+}
+
+// errBranchProtectionRulesTruncated marks failures after the first page of rules, which
+// would leave a truncated list behind.
+var errBranchProtectionRulesTruncated = errors.New("branch protection rules are incomplete")
+
+// CollectBranchProtectionRules pages through all branch protection rules of a repository
+// and through every allowance connection of each rule. It fails instead of returning
+// a truncated list, since a missing rule or allowance would be removed on the next apply.
+// Such failures wrap errBranchProtectionRulesTruncated.
+func CollectBranchProtectionRules(client GraphQLClient, owner, repo string, dumpManager *file.DumpManager) ([]BranchProtectionRuleNode, error) {
+	vars := map[string]interface{}{
+		"owner":  githubv4.String(owner),
+		"name":   githubv4.String(repo),
+		"cursor": (*githubv4.String)(nil),
+	}
+
+	legacy := false
+	fetchPage := func() (BranchProtectionRulesGraphQLQuery, error) {
+		if legacy {
+			var query legacyBranchProtectionRulesGraphQLQuery
+			err := client.Query(context.Background(), &query, vars)
+			return query.toQuery(), err
+		}
+		var query BranchProtectionRulesGraphQLQuery
+		err := client.Query(context.Background(), &query, vars)
+		return query, err
+	}
+
+	var rules []BranchProtectionRuleNode
+	for page := 1; ; page++ {
+		query, err := fetchPage()
+		if page == 1 && isGraphQLSchemaError(err) {
+			fmt.Printf("server lacks newer branch protection fields, importing %s/%s without them: %v\n", owner, repo, err)
+			legacy = true
+			query, err = fetchPage()
+		}
+		if err != nil {
+			if page > 1 {
+				err = fmt.Errorf("%w: %w", errBranchProtectionRulesTruncated, err)
+			}
+			return nil, fmt.Errorf("failed to fetch branch protection rules page %d: %w", page, err)
+		}
+
+		nodes := query.Repository.BranchProtectionRules.Nodes
+		for idx := range nodes {
+			if err := collectRemainingAllowances(client, &nodes[idx]); err != nil {
+				return nil, fmt.Errorf("%w: failed to fetch allowances of branch protection rule %q: %w", errBranchProtectionRulesTruncated, nodes[idx].Pattern, err)
+			}
+		}
+		rules = append(rules, nodes...)
+
+		// Dumped after the allowances are complete, so the dump can be imported offline.
+		filename := fmt.Sprintf("branch_protection_rules-graphql-page_%d.json", page)
+		if err := dumpManager.WriteJSONFile(filename, query); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		pageInfo := query.Repository.BranchProtectionRules.PageInfo
+		if !pageInfo.HasNextPage {
+			break
+		}
+		vars["cursor"] = githubv4.NewString(pageInfo.EndCursor)
+	}
+
+	return rules, nil
+}
+
+func collectRemainingAllowances(client GraphQLClient, rule *BranchProtectionRuleNode) error {
+	connections := []struct {
+		name       string
+		allowances *AllowanceWrapper
+		newQuery   func() allowancesPageQuery
+	}{
+		{"bypassPullRequestAllowances", &rule.BypassPullRequestAllowances, func() allowancesPageQuery { return &bypassPullRequestAllowancesPageQuery{} }},
+		{"reviewDismissalAllowances", &rule.ReviewDismissalAllowances, func() allowancesPageQuery { return &reviewDismissalAllowancesPageQuery{} }},
+		{"bypassForcePushAllowances", &rule.BypassForcePushAllowances, func() allowancesPageQuery { return &bypassForcePushAllowancesPageQuery{} }},
+		{"pushAllowances", &rule.PushAllowances, func() allowancesPageQuery { return &pushAllowancesPageQuery{} }},
+	}
+
+	for _, connection := range connections {
+		pageInfo := connection.allowances.PageInfo
+		for pageInfo.HasNextPage {
+			vars := map[string]interface{}{
+				"id":     rule.ID,
+				"cursor": githubv4.NewString(pageInfo.EndCursor),
+			}
+
+			query := connection.newQuery()
+			if err := client.Query(context.Background(), query, vars); err != nil {
+				return fmt.Errorf("failed to fetch %s after cursor %q: %w", connection.name, pageInfo.EndCursor, err)
+			}
+
+			page := query.allowances()
+			connection.allowances.Nodes = append(connection.allowances.Nodes, page.Nodes...)
+			pageInfo = page.PageInfo
+		}
+		connection.allowances.PageInfo = pageInfo
+	}
+
+	return nil
 }
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+func TestCollectBranchProtectionRulesPaginates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		switch {
+		case strings.Contains(body.Query, "node(id: $id)"):
+			assert.Equal(t, "rule-main", body.Variables["id"])
+			assert.Equal(t, "push-1", body.Variables["cursor"])
+			_, _ = w.Write([]byte(`{"data":{"node":{"pushAllowances":{
+				"nodes":[{"actor":{"login":"second-user"}}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"push-2"}}}}}`))
+		case body.Variables["cursor"] == nil:
+			_, _ = w.Write([]byte(`{"data":{"repository":{"branchProtectionRules":{
+				"nodes":[{"id":"rule-main","pattern":"main",
+					"pushAllowances":{"nodes":[{"actor":{"login":"first-user"}}],"pageInfo":{"hasNextPage":true,"endCursor":"push-1"}}}],
+				"pageInfo":{"hasNextPage":true,"endCursor":"rules-1"}}}}}`))
+		default:
+			assert.Equal(t, "rules-1", body.Variables["cursor"])
+			_, _ = w.Write([]byte(`{"data":{"repository":{"branchProtectionRules":{
+				"nodes":[{"id":"rule-release","pattern":"release/*"}],
+				"pageInfo":{"hasNextPage":false,"endCursor":"rules-2"}}}}}`))
+		}
+	}))
+	defer server.Close()
+
+	dumpManager, err := file.NewDumpManager(t.TempDir(), "owner/repo")
+	require.NoError(t, err)
+
+	client := githubv4.NewEnterpriseClient(server.URL, server.Client())
+	rules, err := CollectBranchProtectionRules(client, "owner", "repo", dumpManager)
+	require.NoError(t, err)
+
+	require.Len(t, rules, 2)
+	assert.Equal(t, githubv4.String("main"), rules[0].Pattern)
+	assert.Equal(t, []string{"/first-user", "/second-user"}, resolveActors(rules[0].PushAllowances.Nodes))
+	assert.Equal(t, githubv4.String("release/*"), rules[1].Pattern)
+}
+
+func TestCollectBranchProtectionRulesErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		failingPage   int
+		wantTruncated bool
+	}{
+		{name: "failing first page leaves nothing behind", failingPage: 1, wantTruncated: false},
+		{name: "failing later page truncates the rules", failingPage: 2, wantTruncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Variables map[string]interface{} `json:"variables"`
+				}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+				page := 1
+				if body.Variables["cursor"] != nil {
+					page = 2
+				}
+				if page == tt.failingPage {
+					_, _ = w.Write([]byte(`{"errors":[{"message":"Resource not accessible by integration"}]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"data":{"repository":{"branchProtectionRules":{
+					"nodes":[{"id":"rule-main","pattern":"main"}],
+					"pageInfo":{"hasNextPage":true,"endCursor":"rules-1"}}}}}`))
+			}))
+			defer server.Close()
+
+			dumpManager, err := file.NewDumpManager("", "owner/repo")
+			require.NoError(t, err)
+
+			client := githubv4.NewEnterpriseClient(server.URL, server.Client())
+			rules, err := CollectBranchProtectionRules(client, "owner", "repo", dumpManager)
+			require.Error(t, err)
+			assert.Nil(t, rules)
+			assert.Equal(t, tt.wantTruncated, errors.Is(err, errBranchProtectionRulesTruncated))
+		})
+	}
+}
+
+func TestCollectBranchProtectionRulesOnOlderServers(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Query)
+
+		if strings.Contains(body.Query, "lockBranch") {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Field 'lockBranch' doesn't exist on type 'BranchProtectionRule'"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"repository":{"branchProtectionRules":{
+			"nodes":[{"id":"rule-main","pattern":"main","isAdminEnforced":true}],
+			"pageInfo":{"hasNextPage":false}}}}}`))
+	}))
+	defer server.Close()
+
+	dumpManager, err := file.NewDumpManager("", "owner/repo")
+	require.NoError(t, err)
+
+	client := githubv4.NewEnterpriseClient(server.URL, server.Client())
+	rules, err := CollectBranchProtectionRules(client, "owner", "repo", dumpManager)
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	for _, field := range []string{"lockBranch", "requireLastPushApproval", "bypassForcePushAllowances"} {
+		assert.NotContains(t, queries[1], field)
+	}
+	require.Len(t, rules, 1)
+	assert.Equal(t, githubv4.String("main"), rules[0].Pattern)
+	assert.True(t, rules[0].IsAdminEnforced)
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// Checkpoint records the repositories a bulk import has finished, so an interrupted run
+// can be resumed without importing them again. It belongs to the configuration file, owner
+// and incremental cutoff it was created with and is discarded when any of them changes, since
+// those select the repositories the run imports.
+type Checkpoint struct {
+	RunHash   string   `json:"run_hash"`
+	Completed []string `json:"completed"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// NewCheckpoint starts an empty checkpoint at path, replacing any previous one once the
+// first repository completes.
+func NewCheckpoint(path, runHash string) *Checkpoint {
+	return &Checkpoint{RunHash: runHash, Completed: []string{}, path: path}
+}
+
+// LoadCheckpoint resumes the checkpoint at path. A missing checkpoint, or one written for
+// a different run, yields an empty checkpoint.
+func LoadCheckpoint(path, runHash string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return NewCheckpoint(path, runHash), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint %q: %w", path, err)
+	}
+
+	checkpoint := NewCheckpoint(path, runHash)
+	var stored Checkpoint
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %q: %w", path, err)
+	}
+
+	if stored.RunHash != runHash {
+		fmt.Printf("configuration, owner or incremental cutoff changed since checkpoint %s was written, starting over\n", path)
+		return checkpoint, nil
+	}
+
+	checkpoint.Completed = append(checkpoint.Completed, stored.Completed...)
+	fmt.Printf("resuming from checkpoint %s, %d repositories already imported\n", path, len(checkpoint.Completed))
+	return checkpoint, nil
+}
+
+func (c *Checkpoint) IsCompleted(repoName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Contains(c.Completed, repoName)
+}
+
+// MarkCompleted records a finished repository and persists the checkpoint right away, so
+// nothing is lost when the run is killed.
+func (c *Checkpoint) MarkCompleted(repoName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Completed = append(c.Completed, repoName)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Written atomically, so a killed run never leaves a truncated checkpoint.
+	if err := file.WriteFileAtomic(c.path, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint once the run it belongs to has finished.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint %q: %w", c.path, err)
+	}
+	return nil
+}
+
+// RunHash identifies what a bulk import run selects: the contents of its configuration file,
+// the owner it is restricted to and the cutoff of an incremental import, as given.
+func RunHash(configFilePath, owner, since string) (string, error) {
+	data, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write(data)
+	// NUL never appears in an owner or a cutoff, so different pairs never hash the same.
+	fmt.Fprintf(h, "\x00owner=%s\x00since=%s", owner, since)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	checkpoint, err := LoadCheckpoint(path, "hash-1")
+	require.NoError(t, err)
+	assert.False(t, checkpoint.IsCompleted("acme/a"))
+
+	require.NoError(t, checkpoint.MarkCompleted("acme/a"))
+	require.NoError(t, checkpoint.MarkCompleted("acme/b"))
+
+	tests := []struct {
+		name      string
+		runHash   string
+		completed []string
+	}{
+		{
+			name:      "same run resumes",
+			runHash:   "hash-1",
+			completed: []string{"acme/a", "acme/b"},
+		},
+		{
+			name:      "different run starts over",
+			runHash:   "hash-2",
+			completed: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resumed, err := LoadCheckpoint(path, tt.runHash)
+			require.NoError(t, err)
+			assert.Equal(t, tt.completed, resumed.Completed)
+		})
+	}
+
+	require.NoError(t, checkpoint.Remove())
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NoError(t, checkpoint.Remove(), "removing a missing checkpoint is not an error")
+}
+
+func TestRunHash(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "import-config.yaml")
+	otherConfigFile := filepath.Join(dir, "other-config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("page_size: 100\n"), 0o644))
+	require.NoError(t, os.WriteFile(otherConfigFile, []byte("page_size: 50\n"), 0o644))
+
+	base, err := RunHash(configFile, "acme", "24h")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		configFile string
+		owner      string
+		since      string
+		wantSame   bool
+	}{
+		{name: "same run", configFile: configFile, owner: "acme", since: "24h", wantSame: true},
+		{name: "changed configuration", configFile: otherConfigFile, owner: "acme", since: "24h"},
+		{name: "other owner", configFile: configFile, owner: "labs", since: "24h"},
+		{name: "other cutoff", configFile: configFile, owner: "acme", since: "2h"},
+		{name: "full import", configFile: configFile, owner: "acme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runHash, err := RunHash(tt.configFile, tt.owner, tt.since)
+			require.NoError(t, err)
+			if tt.wantSame {
+				assert.Equal(t, base, runHash)
+			} else {
+				assert.NotEqual(t, base, runHash)
+			}
+		})
+	}
+
+	_, err = RunHash(filepath.Join(dir, "missing.yaml"), "acme", "")
+	assert.Error(t, err)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/google/go-github/v67/github"
@@ -13,6 +16,100 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// ClientConfig selects how the importer authenticates against GitHub.
+// When AppID is set the importer authenticates as a GitHub App installation,
+// otherwise it falls back to a personal or workflow token.
+type ClientConfig struct {
+	AppID             int64
+	AppInstallationID int64
+	// AppOwner is used to look up the installation when AppInstallationID is not set.
+	AppOwner          string
+	AppPrivateKeyFile string
+
+	// BaseURL, UploadURL and GraphQLURL point the clients at a GitHub Enterprise Server.
+	// Left empty, the clients talk to github.com.
+	BaseURL    string
+	UploadURL  string
+	GraphQLURL string
+
+	// RetryPolicy governs retries on rate limits and transient errors. Nil uses DefaultRetryPolicy.
+	RetryPolicy *RetryPolicy
+}
+
+// ClientConfigFromEnv reads the GitHub App settings from GITHUB_APP_ID,
+// GITHUB_APP_INSTALLATION_ID, GITHUB_APP_PRIVATE_KEY_FILE and OWNER, and the API
+// endpoints from IMPORTER_API_URL, IMPORTER_UPLOAD_URL and IMPORTER_GRAPHQL_URL.
+// The GITHUB_API_URL and GITHUB_GRAPHQL_URL variables are left alone: every GitHub Actions
+// runner sets them, so they would always override the endpoints of the config file.
+func ClientConfigFromEnv() (ClientConfig, error) {
+	cfg := ClientConfig{
+		AppOwner:          os.Getenv("OWNER"),
+		AppPrivateKeyFile: os.Getenv("GITHUB_APP_PRIVATE_KEY_FILE"),
+		BaseURL:           os.Getenv("IMPORTER_API_URL"),
+		UploadURL:         os.Getenv("IMPORTER_UPLOAD_URL"),
+		GraphQLURL:        os.Getenv("IMPORTER_GRAPHQL_URL"),
+	}
+
+	var err error
+	if cfg.AppID, err = parseIDEnv("GITHUB_APP_ID"); err != nil {
+		return ClientConfig{}, err
+	}
+	if cfg.AppInstallationID, err = parseIDEnv("GITHUB_APP_INSTALLATION_ID"); err != nil {
+		return ClientConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// WithEndpointDefaults fills endpoints that neither a flag nor the environment set,
+// and the retry policy, from the import configuration file.
+func (c ClientConfig) WithEndpointDefaults(cfg Config) ClientConfig {
+	if c.BaseURL == "" && cfg.BaseURL != nil {
+		c.BaseURL = *cfg.BaseURL
+	}
+	if c.UploadURL == "" && cfg.UploadURL != nil {
+		c.UploadURL = *cfg.UploadURL
+	}
+	if c.GraphQLURL == "" && cfg.GraphQLURL != nil {
+		c.GraphQLURL = *cfg.GraphQLURL
+	}
+	if c.RetryPolicy == nil && cfg.RateLimit != nil {
+		policy := cfg.RateLimit.RetryPolicy()
+		c.RetryPolicy = &policy
+	}
+	return c
+}
+
+func (c ClientConfig) usesApp() bool {
+	return c.AppID != 0
+}
+
+func (c ClientConfig) Validate() error {
+	if !c.usesApp() {
+		return nil
+	}
+	if c.AppPrivateKeyFile == "" {
+		return errors.New("app private key file must be provided when authenticating as a GitHub App")
+	}
+	if c.AppInstallationID == 0 && c.AppOwner == "" {
+		return errors.New("app installation id or owner must be provided when authenticating as a GitHub App")
+	}
+	return nil
+}
+
+func parseIDEnv(name string) (int64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return id, nil
+}
+
 func getToken() (string, error) {
 	token := os.Getenv("GITHUB_TOKEN")
 
@@ -33,16 +130,98 @@ func getToken() (string, error) {
 	return "", errors.New("retrieved token is empty")
 }
 
-func CreateGitHubClient() (*github.Client, *githubv4.Client, error) {
-	token, err := getToken()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to retrieve token: %w", err)
+func CreateGitHubClient(cfg ClientConfig) (*github.Client, *githubv4.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid client configuration: %w", err)
 	}
 
 	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+
+	newRESTClient := func(hc *http.Client) (*github.Client, error) {
+		return newEnterpriseRESTClient(hc, cfg)
+	}
+
+	var tc *http.Client
+	if cfg.usesApp() {
+		var err error
+		tc, err = newAppHTTPClient(ctx, cfg, newRESTClient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to authenticate as GitHub App %d: %w", cfg.AppID, err)
+		}
+	} else {
+		token, err := getToken()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to retrieve token: %w", err)
+		}
+		tc = oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+	}
+
+	tc.Transport = newRetryTransport(tc.Transport, cfg.retryPolicy())
+
+	client, err := newRESTClient(tc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid API URL: %w", err)
+	}
+
 	v4client := githubv4.NewClient(tc)
+	if graphQLURL := cfg.graphQLURL(); graphQLURL != "" {
+		fmt.Printf("using GitHub API at %s (GraphQL: %s)\n", client.BaseURL, graphQLURL)
+		v4client = githubv4.NewEnterpriseClient(graphQLURL, tc)
+	}
 	return client, v4client, nil
 }
+
+func newEnterpriseRESTClient(hc *http.Client, cfg ClientConfig) (*github.Client, error) {
+	client := github.NewClient(hc)
+	if cfg.BaseURL == "" || isGitHubDotCom(cfg.BaseURL) {
+		return client, nil
+	}
+
+	uploadURL := cfg.UploadURL
+	if uploadURL == "" {
+		// WithEnterpriseURLs appends api/uploads/ to the server root.
+		uploadURL = strings.TrimSuffix(strings.TrimSuffix(cfg.BaseURL, "/"), "/api/v3")
+	}
+	return client.WithEnterpriseURLs(cfg.BaseURL, uploadURL)
+}
+
+func (c ClientConfig) retryPolicy() RetryPolicy {
+	if c.RetryPolicy != nil {
+		return *c.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+// graphQLURL returns the GraphQL endpoint to use, or "" for github.com. GitHub Enterprise
+// Server serves GraphQL from /api/graphql, which is derived from BaseURL when not set.
+func (c ClientConfig) graphQLURL() string {
+	if c.GraphQLURL != "" {
+		if isGitHubDotCom(c.GraphQLURL) {
+			return ""
+		}
+		return c.GraphQLURL
+	}
+	if c.BaseURL == "" || isGitHubDotCom(c.BaseURL) {
+		return ""
+	}
+
+	u, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return ""
+	}
+	u.Path = "/api/graphql"
+	return u.String()
+}
+
+// isGitHubDotCom reports whether an endpoint belongs to github.com, which the default
+// clients already talk to.
+func isGitHubDotCom(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Host == "api.github.com"
+}
+
+// isUnsupported reports whether a request failed because the server does not know the
+// endpoint, as happens on GitHub Enterprise Server versions that predate it.
+func isUnsupported(r *github.Response) bool {
+	return r != nil && r.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,76 @@
+package github
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientConfigEndpoints(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            ClientConfig
+		fileConfig     Config
+		wantBaseURL    string
+		wantUploadURL  string
+		wantGraphQLURL string
+	}{
+		{
+			name:           "github.com by default",
+			wantBaseURL:    "https://api.github.com/",
+			wantUploadURL:  "https://uploads.github.com/",
+			wantGraphQLURL: "",
+		},
+		{
+			name:           "enterprise server derives upload and graphql URLs",
+			cfg:            ClientConfig{BaseURL: "https://github.example.com"},
+			wantBaseURL:    "https://github.example.com/api/v3/",
+			wantUploadURL:  "https://github.example.com/api/uploads/",
+			wantGraphQLURL: "https://github.example.com/api/graphql",
+		},
+		{
+			name:           "config file fills endpoints not set by flags or env",
+			cfg:            ClientConfig{GraphQLURL: "https://graphql.example.com/graphql"},
+			fileConfig:     Config{BaseURL: github.String("https://github.example.com/api/v3/"), GraphQLURL: github.String("https://ignored.example.com")},
+			wantBaseURL:    "https://github.example.com/api/v3/",
+			wantUploadURL:  "https://github.example.com/api/uploads/",
+			wantGraphQLURL: "https://graphql.example.com/graphql",
+		},
+		{
+			name:           "github.com endpoints use the default clients",
+			cfg:            ClientConfig{BaseURL: "https://api.github.com", GraphQLURL: "https://api.github.com/graphql"},
+			wantBaseURL:    "https://api.github.com/",
+			wantUploadURL:  "https://uploads.github.com/",
+			wantGraphQLURL: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg.WithEndpointDefaults(tt.fileConfig)
+
+			client, err := newEnterpriseRESTClient(http.DefaultClient, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBaseURL, client.BaseURL.String())
+			assert.Equal(t, tt.wantUploadURL, client.UploadURL.String())
+			assert.Equal(t, tt.wantGraphQLURL, cfg.graphQLURL())
+		})
+	}
+}
+
+func TestClientConfigFromEnvIgnoresRunnerEndpoints(t *testing.T) {
+	t.Setenv("GITHUB_API_URL", "https://api.github.com")
+	t.Setenv("GITHUB_GRAPHQL_URL", "https://api.github.com/graphql")
+	t.Setenv("IMPORTER_API_URL", "")
+	t.Setenv("IMPORTER_GRAPHQL_URL", "https://github.example.com/api/graphql")
+
+	cfg, err := ClientConfigFromEnv()
+	require.NoError(t, err)
+
+	cfg = cfg.WithEndpointDefaults(Config{BaseURL: github.String("https://github.example.com/api/v3/")})
+	assert.Equal(t, "https://github.example.com/api/v3/", cfg.BaseURL)
+	assert.Equal(t, "https://github.example.com/api/graphql", cfg.GraphQLURL)
+}
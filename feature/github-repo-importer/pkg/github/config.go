@@ -2,18 +2,205 @@ package github
 
 import (
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	IsPublic      *bool    `yaml:"is_public,omitempty"`
-	IgnoredRepos  []string `yaml:"ignored_repos,omitempty"`
-	SelectedRepos []string `yaml:"selected_repos,omitempty"`
-	PageSize      *int     `yaml:"page_size,omitempty"`
+	IsPublic             *bool            `yaml:"is_public,omitempty"`
+	IgnoredRepos         []string         `yaml:"ignored_repos,omitempty"`
+	SelectedRepos        []string         `yaml:"selected_repos,omitempty"`
+	PageSize             *int             `yaml:"page_size,omitempty"`
+	ExcludeDefaultLabels *bool            `yaml:"exclude_default_labels,omitempty"`
+	BaseURL              *string          `yaml:"base_url,omitempty"`
+	UploadURL            *string          `yaml:"upload_url,omitempty"`
+	GraphQLURL           *string          `yaml:"graphql_url,omitempty"`
+	Concurrency          *int             `yaml:"concurrency,omitempty"`
+	RateLimit            *RateLimitConfig `yaml:"rate_limit,omitempty"`
+	MaxFailures          *int             `yaml:"max_failures,omitempty"`
+	Filters              *RepoFilters     `yaml:"filters,omitempty"`
+	// OutputDir receives the generated <owner>/<repo>.yaml files, DumpDir the raw API
+	// responses. NoDumps skips the dumps altogether.
+	OutputDir *string `yaml:"output_dir,omitempty"`
+	DumpDir   *string `yaml:"dump_dir,omitempty"`
+	NoDumps   *bool   `yaml:"no_dumps,omitempty"`
+	// Organizations imports several organizations in one run. The top level is_public and
+	// filters are their defaults, the top level ignored_repos and selected_repos are added
+	// to the lists of the organizations owning the repositories.
+	Organizations []OrganizationConfig `yaml:"organizations,omitempty"`
+}
+
+// OrganizationConfig selects the repositories of one organization. Repository names may
+// omit the owner. is_public and filters default to the top level ones.
+type OrganizationConfig struct {
+	Name          string       `yaml:"name"`
+	IsPublic      *bool        `yaml:"is_public,omitempty"`
+	IgnoredRepos  []string     `yaml:"ignored_repos,omitempty"`
+	SelectedRepos []string     `yaml:"selected_repos,omitempty"`
+	Filters       *RepoFilters `yaml:"filters,omitempty"`
+}
+
+// RateLimitConfig overrides single fields of DefaultRetryPolicy.
+type RateLimitConfig struct {
+	MaxRetries   *int           `yaml:"max_retries,omitempty"`
+	MaxWait      *time.Duration `yaml:"max_wait,omitempty"`
+	MinRemaining *int           `yaml:"min_remaining,omitempty"`
+}
+
+func (c *RateLimitConfig) RetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy
+	if c.MaxRetries != nil {
+		policy.MaxRetries = *c.MaxRetries
+	}
+	if c.MaxWait != nil {
+		policy.MaxWait = *c.MaxWait
+	}
+	if c.MinRemaining != nil {
+		policy.MinRemaining = *c.MinRemaining
+	}
+	return policy
 }
 
 func (c *Config) Validate() error {
 	if len(c.IgnoredRepos) > 0 && len(c.SelectedRepos) > 0 {
 		return errors.New("only one list of ignored_repos or selected_repos must be provided")
 	}
+	seen := make(map[string]bool)
+	for _, org := range c.Organizations {
+		if org.Name == "" {
+			return errors.New("every organization must have a name")
+		}
+		if seen[strings.ToLower(org.Name)] {
+			return fmt.Errorf("organization %s is listed more than once", org.Name)
+		}
+		seen[strings.ToLower(org.Name)] = true
+
+		if len(org.IgnoredRepos) > 0 && len(org.SelectedRepos) > 0 {
+			return fmt.Errorf("only one list of ignored_repos or selected_repos must be provided for organization %s", org.Name)
+		}
+		if org.Filters != nil {
+			if err := org.Filters.Validate(); err != nil {
+				return fmt.Errorf("invalid filters of organization %s: %w", org.Name, err)
+			}
+		}
+		isPublic, filters := org.IsPublic, org.Filters
+		if isPublic == nil {
+			isPublic = c.IsPublic
+		}
+		if filters == nil {
+			filters = c.Filters
+		}
+		if err := validateVisibility(isPublic, filters); err != nil {
+			return fmt.Errorf("invalid organization %s: %w", org.Name, err)
+		}
+	}
+	if c.Concurrency != nil && *c.Concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+	if c.Filters != nil {
+		if err := c.Filters.Validate(); err != nil {
+			return fmt.Errorf("invalid filters: %w", err)
+		}
+	}
+	if err := validateVisibility(c.IsPublic, c.Filters); err != nil {
+		return err
+	}
+	if c.MaxFailures != nil && *c.MaxFailures < 0 {
+		return errors.New("max_failures must not be negative")
+	}
+	if c.RateLimit != nil {
+		policy := c.RateLimit.RetryPolicy()
+		if policy.MaxRetries < 0 || policy.MaxWait < 0 || policy.MinRemaining < 0 {
+			return errors.New("rate_limit settings must not be negative")
+		}
+	}
 	return nil
 }
+
+// validateVisibility rejects is_public next to filters.visibility, inherited ones included,
+// since both select the visibility to import.
+func validateVisibility(isPublic *bool, filters *RepoFilters) error {
+	if isPublic != nil && filters != nil && len(filters.Visibility) > 0 {
+		return errors.New("only one of is_public or filters.visibility must be provided")
+	}
+	return nil
+}
+
+// ResolveOrganizations returns the organizations to import. A non-empty owner, usually the
+// OWNER environment variable, restricts the import to that organization, using its entry in
+// organizations when there is one and the top level lists otherwise.
+func (c *Config) ResolveOrganizations(owner string) ([]OrganizationConfig, error) {
+	var orgs []OrganizationConfig
+	// Configured organizations inherit the top level repositories they own.
+	inheritRepos := true
+	switch {
+	case owner != "":
+		org := OrganizationConfig{
+			Name:          owner,
+			IsPublic:      c.IsPublic,
+			IgnoredRepos:  c.IgnoredRepos,
+			SelectedRepos: c.SelectedRepos,
+			Filters:       c.Filters,
+		}
+		inheritRepos = false
+		for _, configured := range c.Organizations {
+			if strings.EqualFold(configured.Name, owner) {
+				org = configured
+				inheritRepos = true
+			}
+		}
+		orgs = []OrganizationConfig{org}
+	case len(c.Organizations) > 0:
+		orgs = slices.Clone(c.Organizations)
+	default:
+		return nil, errors.New("no organization to import, set OWNER or list organizations in the config file")
+	}
+
+	for idx := range orgs {
+		if orgs[idx].IsPublic == nil {
+			orgs[idx].IsPublic = c.IsPublic
+		}
+		if orgs[idx].Filters == nil {
+			orgs[idx].Filters = c.Filters
+		}
+		orgs[idx].IgnoredRepos = qualifyRepoNames(orgs[idx].Name, orgs[idx].IgnoredRepos)
+		orgs[idx].SelectedRepos = qualifyRepoNames(orgs[idx].Name, orgs[idx].SelectedRepos)
+		if inheritRepos {
+			orgs[idx].IgnoredRepos = appendOwnedRepoNames(orgs[idx].Name, orgs[idx].IgnoredRepos, c.IgnoredRepos)
+			orgs[idx].SelectedRepos = appendOwnedRepoNames(orgs[idx].Name, orgs[idx].SelectedRepos, c.SelectedRepos)
+		}
+
+		if len(orgs[idx].IgnoredRepos) > 0 && len(orgs[idx].SelectedRepos) > 0 {
+			return nil, fmt.Errorf("organization %s ends up with both ignored_repos and selected_repos, including the top level ones", orgs[idx].Name)
+		}
+	}
+	return orgs, nil
+}
+
+// appendOwnedRepoNames appends the repositories of owner among repoNames that are not listed
+// yet. Names without an owner belong to every owner.
+func appendOwnedRepoNames(owner string, listed, repoNames []string) []string {
+	for _, repoName := range qualifyRepoNames(owner, repoNames) {
+		repoOwner, _, _ := strings.Cut(repoName, "/")
+		if strings.EqualFold(repoOwner, owner) && !slices.ContainsFunc(listed, func(name string) bool {
+			return strings.EqualFold(name, repoName)
+		}) {
+			listed = append(listed, repoName)
+		}
+	}
+	return listed
+}
+
+// qualifyRepoNames prefixes repository names given without their owner.
+func qualifyRepoNames(owner string, repoNames []string) []string {
+	var qualified []string
+	for _, repoName := range repoNames {
+		if !strings.Contains(repoName, "/") {
+			repoName = owner + "/" + repoName
+		}
+		qualified = append(qualified, repoName)
+	}
+	return qualified
+}
@@ -19,8 +19,9 @@ const (
 	RuleCodeScanning              = "code_scanning"
 
 	// Visibility
-	VisibilityPrivate = "private"
-	VisibilityPublic  = "public"
+	VisibilityPrivate  = "private"
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
 
 	// Permission levels
 	PermissionRead     = "read"
@@ -31,5 +32,14 @@ const (
 	PermissionMaintain = "maintain"
 	PermissionAdmin    = "admin"
 
-	DefaultPageSize = 100
+	// Allowed actions
+	AllowedActionsAll       = "all"
+	AllowedActionsLocalOnly = "local_only"
+	AllowedActionsSelected  = "selected"
+
+	DefaultPageSize    = 100
+	DefaultConcurrency = 4
+
+	DefaultOutputDir = "./configs"
+	DefaultDumpDir   = "./dumps"
 )
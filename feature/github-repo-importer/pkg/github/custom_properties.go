@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type CustomPropertySchema struct {
+	Name             string   `yaml:"name"`
+	ValueType        string   `yaml:"value_type"`
+	Required         *bool    `yaml:"required,omitempty"`
+	DefaultValue     *string  `yaml:"default_value,omitempty"`
+	Description      *string  `yaml:"description,omitempty"`
+	AllowedValues    []string `yaml:"allowed_values,omitempty"`
+	ValuesEditableBy *string  `yaml:"values_editable_by,omitempty"`
+}
+
+func CollectCustomProperties(client *github.Client, owner, repo string, dumpManager *file.DumpManager) (map[string]interface{}, error) {
+	values, r, err := client.Repositories.GetAllCustomPropertyValues(context.Background(), owner, repo)
+	if err != nil {
+		if isUnsupported(r) {
+			fmt.Printf("skipping custom properties, not supported by the server: %v\n", err)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get custom property values: %w", err)
+	}
+
+	if err := dumpManager.WriteJSONFile("custom_properties.json", values); err != nil {
+		fmt.Printf("failed to write custom_properties.json: %v\n", err)
+	}
+
+	return resolveCustomProperties(values), nil
+}
+
+// ImportCustomPropertySchema fetches the custom property definitions of an organization.
+func (i *Importer) ImportCustomPropertySchema(org string) ([]CustomPropertySchema, error) {
+	dumpManager, err := file.NewDumpManager(i.dumpDir, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new dump manager: %w", err)
+	}
+
+	properties, _, err := i.v3client.Organizations.GetAllCustomProperties(context.Background(), org)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom properties of %s: %w", org, err)
+	}
+
+	if err := dumpManager.WriteJSONFile("custom_properties_schema.json", properties); err != nil {
+		fmt.Printf("failed to write custom_properties_schema.json: %v\n", err)
+	}
+
+	return resolveCustomPropertySchema(properties), nil
+}
+
+func resolveCustomProperties(values []*github.CustomPropertyValue) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, value := range values {
+		if value == nil || value.Value == nil {
+			continue
+		}
+		properties[value.PropertyName] = value.Value
+	}
+
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
+
+func resolveCustomPropertySchema(properties []*github.CustomProperty) []CustomPropertySchema {
+	var schema []CustomPropertySchema
+	for _, property := range properties {
+		if property == nil {
+			continue
+		}
+
+		schema = append(schema, CustomPropertySchema{
+			Name:             property.GetPropertyName(),
+			ValueType:        property.ValueType,
+			Required:         property.Required,
+			DefaultValue:     property.DefaultValue,
+			Description:      property.Description,
+			AllowedValues:    property.AllowedValues,
+			ValuesEditableBy: property.ValuesEditableBy,
+		})
+	}
+	return schema
+}
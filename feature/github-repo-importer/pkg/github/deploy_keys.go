@@ -0,0 +1,65 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type DeployKey struct {
+	Title    string `yaml:"title"`
+	Key      string `yaml:"key"`
+	ReadOnly *bool  `yaml:"read_only,omitempty"`
+}
+
+func CollectDeployKeys(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]DeployKey, error) {
+	var deployKeys []DeployKey
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		keys, resp, err := client.Repositories.ListKeys(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deploy keys: %w", err)
+		}
+
+		filename := fmt.Sprintf("deploy_keys-page_%d.json", opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, keys); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		deployKeys = append(deployKeys, resolveDeployKeys(keys)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return deployKeys, nil
+}
+
+func resolveDeployKeys(keys []*github.Key) []DeployKey {
+	var deployKeys []DeployKey
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+
+		deployKeys = append(deployKeys, DeployKey{
+			Title:    key.GetTitle(),
+			Key:      key.GetKey(),
+			ReadOnly: key.ReadOnly,
+		})
+	}
+	return deployKeys
+}
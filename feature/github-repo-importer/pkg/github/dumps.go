@@ -0,0 +1,296 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// ImportRepoFromDumps rebuilds a repository from the raw API responses an earlier import
+// dumped under dumpsDir/<owner>/<repo>, without talking to GitHub. The responses go through
+// the same conversion as a live import, so conversion changes can be replayed against
+// recorded data. Settings without a dump are left out of the result.
+func ImportRepoFromDumps(dumpsDir, repoName string, opts ImportOptions) (*Repository, error) {
+	fmt.Println("Importing repository from dumps: ", repoName)
+
+	if !isValidRepoFormat(repoName) {
+		return nil, errors.New("invalid repository format. Use owner/repo")
+	}
+
+	dumpReader, err := file.NewDumpReader(filepath.Join(dumpsDir, repoName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new dump reader: %w", err)
+	}
+
+	var repo *github.Repository
+	if ok, err := dumpReader.ReadJSONFile("repository.json", &repo); err != nil {
+		return nil, err
+	} else if !ok || repo == nil {
+		return nil, fmt.Errorf("no repository.json dumped for %s", repoName)
+	}
+
+	var settings repositorySettings
+	var errs []error
+	collect := func(err error) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var collaborators []*github.User
+	collect(readDumpPages(dumpReader, "collaborators", &collaborators))
+	settings.collaborators = resolveCollaborators(collaborators)
+
+	var teams []*github.Team
+	collect(readDumpPages(dumpReader, "teams", &teams))
+	settings.teams = resolveTeams(teams)
+
+	var hooks []*github.Hook
+	collect(readDumpPages(dumpReader, "hooks", &hooks))
+	settings.webhooks = resolveWebhooks(hooks)
+
+	var keys []*github.Key
+	collect(readDumpPages(dumpReader, "deploy_keys", &keys))
+	settings.deployKeys = resolveDeployKeys(keys)
+
+	var labels []*github.Label
+	collect(readDumpPages(dumpReader, "labels", &labels))
+	settings.issueLabels = resolveIssueLabels(labels, opts.ExcludeDefaultLabels)
+
+	var autolinks []*github.Autolink
+	collect(readDumpPages(dumpReader, "autolinks", &autolinks))
+	settings.autolinkReferences = resolveAutolinkReferences(autolinks)
+
+	environments, err := readEnvironmentDumps(dumpReader)
+	collect(err)
+	settings.environments = environments
+
+	secretPages, err := readObjectDumpPages[*github.Secrets](dumpReader, "actions_secrets")
+	collect(err)
+	for _, page := range secretPages {
+		settings.actionsSecrets = append(settings.actionsSecrets, resolveSecrets(page)...)
+	}
+
+	variablePages, err := readObjectDumpPages[*github.ActionsVariables](dumpReader, "actions_variables")
+	collect(err)
+	for _, page := range variablePages {
+		settings.actionsVariables = append(settings.actionsVariables, resolveVariables(page)...)
+	}
+
+	var (
+		permissions         *github.ActionsPermissionsRepository
+		allowed             *github.ActionsAllowed
+		workflowPermissions *github.DefaultWorkflowPermissionRepository
+	)
+	collect(readDumpFile(dumpReader, "actions_permissions.json", &permissions))
+	collect(readDumpFile(dumpReader, "actions_allowed.json", &allowed))
+	collect(readDumpFile(dumpReader, "actions_workflow_permissions.json", &workflowPermissions))
+	settings.actions = resolveActions(permissions, allowed, workflowPermissions)
+
+	collect(readDumpFile(dumpReader, "pages.json", &settings.pages))
+
+	rulesets, err := readRulesetDumps(dumpReader)
+	collect(err)
+	if settings.rulesets, err = resolveRulesets(rulesets); err != nil {
+		fmt.Printf("failed to resolve rulesets: %v\n", err)
+	}
+
+	var vulnerabilityAlerts *struct{ Enabled bool }
+	collect(readDumpFile(dumpReader, "vulnerability_alerts.json", &vulnerabilityAlerts))
+	if vulnerabilityAlerts != nil {
+		settings.vulnerabilityAlertsEnabled = &vulnerabilityAlerts.Enabled
+	}
+
+	var (
+		automatedSecurityFixes  *github.AutomatedSecurityFixes
+		privateReporting        *struct{ Enabled bool }
+		privateReportingEnabled *bool
+	)
+	collect(readDumpFile(dumpReader, "automated_security_fixes.json", &automatedSecurityFixes))
+	collect(readDumpFile(dumpReader, "private_vulnerability_reporting.json", &privateReporting))
+	if privateReporting != nil {
+		privateReportingEnabled = &privateReporting.Enabled
+	}
+	settings.securityAndAnalysis = resolveSecurityAndAnalysis(repo.GetSecurityAndAnalysis(), automatedSecurityFixes, privateReportingEnabled)
+
+	var customProperties []*github.CustomPropertyValue
+	collect(readDumpFile(dumpReader, "custom_properties.json", &customProperties))
+	settings.customProperties = resolveCustomProperties(customProperties)
+
+	settings.branchProtectionRules, err = readBranchProtectionRuleDumps(dumpReader)
+	collect(err)
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return newRepository(repo, settings), nil
+}
+
+func readDumpFile(dumpReader *file.DumpReader, fileName string, data interface{}) error {
+	if ok, err := dumpReader.ReadJSONFile(fileName, data); err != nil {
+		return err
+	} else if !ok {
+		fmt.Printf("no %s dumped, leaving it out\n", fileName)
+	}
+	return nil
+}
+
+// readDumpPages appends the items of every <prefix>-page_N.json dump to items, in page order.
+func readDumpPages[T any](dumpReader *file.DumpReader, prefix string, items *[]T) error {
+	pages, err := dumpReader.Glob(prefix + "-page_*.json")
+	if err != nil {
+		return err
+	}
+
+	for _, page := range pages {
+		var pageItems []T
+		if _, err := dumpReader.ReadJSONFile(page, &pageItems); err != nil {
+			return err
+		}
+		*items = append(*items, pageItems...)
+	}
+	return nil
+}
+
+// readObjectDumpPages reads every <prefix>-page_N.json dump of a list endpoint that wraps
+// its items in an object, such as environments or secrets, in page order.
+func readObjectDumpPages[T any](dumpReader *file.DumpReader, prefix string) ([]T, error) {
+	pages, err := dumpReader.Glob(prefix + "-page_*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []T
+	for _, page := range pages {
+		var object T
+		if _, err := dumpReader.ReadJSONFile(page, &object); err != nil {
+			return nil, err
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+func readEnvironmentDumps(dumpReader *file.DumpReader) ([]Environment, error) {
+	envPages, err := readObjectDumpPages[*github.EnvResponse](dumpReader, "environments")
+	if err != nil {
+		return nil, err
+	}
+
+	var environments []Environment
+	for _, envPage := range envPages {
+		if envPage == nil {
+			continue
+		}
+		for _, env := range envPage.Environments {
+			dumpName := environmentDumpName(env.GetName())
+
+			var branchPolicies []*github.DeploymentBranchPolicy
+			if env.GetDeploymentBranchPolicy().GetCustomBranchPolicies() {
+				if branchPolicies, err = readDeploymentBranchPolicyDumps(dumpReader, dumpName); err != nil {
+					return nil, err
+				}
+			}
+			environment := resolveEnvironment(env, branchPolicies)
+
+			secretPages, err := readObjectDumpPages[*github.Secrets](dumpReader, fmt.Sprintf("environment-%s-secrets", dumpName))
+			if err != nil {
+				return nil, err
+			}
+			for _, page := range secretPages {
+				environment.Secrets = append(environment.Secrets, resolveSecrets(page)...)
+			}
+
+			variablePages, err := readObjectDumpPages[*github.ActionsVariables](dumpReader, fmt.Sprintf("environment-%s-variables", dumpName))
+			if err != nil {
+				return nil, err
+			}
+			for _, page := range variablePages {
+				environment.Variables = append(environment.Variables, resolveVariables(page)...)
+			}
+
+			environments = append(environments, environment)
+		}
+	}
+	return environments, nil
+}
+
+// readDeploymentBranchPolicyDumps reads the paged deployment branch policy dumps of an
+// environment, falling back to the single file written before they were paged.
+func readDeploymentBranchPolicyDumps(dumpReader *file.DumpReader, dumpName string) ([]*github.DeploymentBranchPolicy, error) {
+	pages, err := readObjectDumpPages[*github.DeploymentBranchPolicyResponse](dumpReader, fmt.Sprintf("environment-%s-branch_policies", dumpName))
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		var policies *github.DeploymentBranchPolicyResponse
+		if err := readDumpFile(dumpReader, fmt.Sprintf("environment-%s-branch_policies.json", dumpName), &policies); err != nil {
+			return nil, err
+		}
+		pages = append(pages, policies)
+	}
+
+	var branchPolicies []*github.DeploymentBranchPolicy
+	for _, page := range pages {
+		if page != nil {
+			branchPolicies = append(branchPolicies, page.BranchPolicies...)
+		}
+	}
+	return branchPolicies, nil
+}
+
+func readRulesetDumps(dumpReader *file.DumpReader) ([]github.Ruleset, error) {
+	names, err := dumpReader.Glob("ruleset[0-9]*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var rulesets []github.Ruleset
+	for _, name := range names {
+		var ruleset github.Ruleset
+		if _, err := dumpReader.ReadJSONFile(name, &ruleset); err != nil {
+			return nil, err
+		}
+		rulesets = append(rulesets, ruleset)
+	}
+	return rulesets, nil
+}
+
+func readBranchProtectionRuleDumps(dumpReader *file.DumpReader) ([]BranchProtectionRuleNode, error) {
+	pages, err := readObjectDumpPages[BranchProtectionRulesGraphQLQuery](dumpReader, "branch_protection_rules-graphql")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pages) == 0 {
+		// Imports before the pages were dumped one by one wrote a single file, which can
+		// not be told apart from a complete listing.
+		legacy, err := dumpReader.Glob("branch_protection_rules-graphql.json")
+		if err != nil {
+			return nil, err
+		}
+		if len(legacy) > 0 {
+			return nil, errors.New("branch protection rules were dumped in the old branch_protection_rules-graphql.json layout, import the repository again to refresh its dumps")
+		}
+	}
+
+	var rules []BranchProtectionRuleNode
+	for _, page := range pages {
+		for _, rule := range page.Repository.BranchProtectionRules.Nodes {
+			// Dumps written before allowances were paged completely only hold their first page.
+			for _, allowances := range []AllowanceWrapper{rule.BypassPullRequestAllowances, rule.ReviewDismissalAllowances, rule.BypassForcePushAllowances, rule.PushAllowances} {
+				if allowances.PageInfo.HasNextPage {
+					fmt.Printf("warning: dump of branch protection rule %q holds only the first page of its allowances\n", rule.Pattern)
+					break
+				}
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
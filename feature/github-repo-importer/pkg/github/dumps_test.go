@@ -0,0 +1,76 @@
+package github
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportRepoFromDumpsMatchesLiveImport(t *testing.T) {
+	importer, server, dumpDir := newFakeOrgImporter(t)
+
+	// A ruleset deleted since an earlier import must not come back from its stale dump.
+	require.NoError(t, os.MkdirAll(filepath.Join(dumpDir, "acme/ok"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dumpDir, "acme/ok/ruleset99.json"), []byte(`{"id":99,"name":"deleted"}`), 0o644))
+
+	live, err := importer.ImportRepo("acme/ok", ImportOptions{})
+	require.NoError(t, err)
+	server.Close()
+
+	offline, err := ImportRepoFromDumps(dumpDir, "acme/ok", ImportOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, live, offline)
+	assert.Empty(t, offline.Rulesets)
+	assert.Equal(t, []string{"octocat"}, offline.AdminCollaborators)
+	require.Len(t, offline.Environments, 1)
+	assert.Equal(t, []ActionsVariable{{Name: "REGION", Value: "eu"}}, offline.Environments[0].Variables)
+	require.Len(t, offline.Webhooks, 1)
+	assert.Equal(t, "https://ci.example.com", offline.Webhooks[0].URL)
+}
+
+func TestImportRepoFromDumpsErrors(t *testing.T) {
+	dumpsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(dumpsDir+"/acme/empty", 0o755))
+	require.NoError(t, os.MkdirAll(dumpsDir+"/acme/legacy", 0o755))
+	require.NoError(t, os.WriteFile(dumpsDir+"/acme/legacy/repository.json", []byte(`{"name":"legacy","owner":{"login":"acme"}}`), 0o644))
+	require.NoError(t, os.WriteFile(dumpsDir+"/acme/legacy/branch_protection_rules-graphql.json", []byte(`{}`), 0o644))
+
+	tests := []struct {
+		name     string
+		repoName string
+		errorMsg string
+	}{
+		{
+			name:     "invalid repo format",
+			repoName: "invalid-format",
+			errorMsg: "invalid repository format. Use owner/repo",
+		},
+		{
+			name:     "no dumps",
+			repoName: "acme/missing",
+			errorMsg: "failed to create new dump reader",
+		},
+		{
+			name:     "no repository dump",
+			repoName: "acme/empty",
+			errorMsg: "no repository.json dumped for acme/empty",
+		},
+		{
+			name:     "old branch protection rules layout",
+			repoName: "acme/legacy",
+			errorMsg: "old branch_protection_rules-graphql.json layout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := ImportRepoFromDumps(dumpsDir, tt.repoName, ImportOptions{})
+			assert.Nil(t, repo)
+			assert.ErrorContains(t, err, tt.errorMsg)
+		})
+	}
+}
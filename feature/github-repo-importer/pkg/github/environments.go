@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type Environment struct {
+	Name                   string                  `yaml:"name"`
+	WaitTimer              *int                    `yaml:"wait_timer,omitempty"`
+	CanAdminsBypass        *bool                   `yaml:"can_admins_bypass,omitempty"`
+	PreventSelfReview      *bool                   `yaml:"prevent_self_review,omitempty"`
+	Reviewers              *EnvironmentReviewers   `yaml:"reviewers,omitempty"`
+	DeploymentBranchPolicy *DeploymentBranchPolicy `yaml:"deployment_branch_policy,omitempty"`
+	Secrets                []ActionsSecret         `yaml:"secrets,omitempty"`
+	Variables              []ActionsVariable       `yaml:"variables,omitempty"`
+}
+
+type EnvironmentReviewers struct {
+	Users []string `yaml:"users,omitempty"`
+	Teams []string `yaml:"teams,omitempty"`
+}
+
+type DeploymentBranchPolicy struct {
+	ProtectedBranches    bool     `yaml:"protected_branches"`
+	CustomBranchPolicies bool     `yaml:"custom_branch_policies"`
+	BranchPatterns       []string `yaml:"branch_patterns,omitempty"`
+	TagPatterns          []string `yaml:"tag_patterns,omitempty"`
+}
+
+func CollectEnvironments(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]Environment, error) {
+	var environments []Environment
+
+	opts := &github.EnvironmentListOptions{ListOptions: github.ListOptions{PerPage: 100}}
+
+	for {
+		envResponse, resp, err := client.Repositories.ListEnvironments(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list environments: %w", err)
+		}
+
+		filename := fmt.Sprintf("environments-page_%d.json", opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, envResponse); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		for _, env := range envResponse.Environments {
+			var branchPolicies []*github.DeploymentBranchPolicy
+			if env.GetDeploymentBranchPolicy().GetCustomBranchPolicies() {
+				branchPolicies, err = collectDeploymentBranchPolicies(client, owner, repo, env.GetName(), dumpManager)
+				if err != nil {
+					return nil, fmt.Errorf("failed to list deployment branch policies of environment %q: %w", env.GetName(), err)
+				}
+			}
+
+			environments = append(environments, resolveEnvironment(env, branchPolicies))
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return environments, nil
+}
+
+// collectDeploymentBranchPolicies pages through the deployment branch policies of an
+// environment. go-github's ListDeploymentBranchPolicies takes no list options and only
+// returns the first 30 policies.
+func collectDeploymentBranchPolicies(client *github.Client, owner, repo, env string, dumpManager *file.DumpManager) ([]*github.DeploymentBranchPolicy, error) {
+	var branchPolicies []*github.DeploymentBranchPolicy
+
+	for page := 1; ; page++ {
+		u := fmt.Sprintf("repos/%s/%s/environments/%s/deployment-branch-policies?per_page=100&page=%d", owner, repo, url.PathEscape(env), page)
+		req, err := client.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		policies := new(github.DeploymentBranchPolicyResponse)
+		resp, err := client.Do(context.Background(), req, policies)
+		if err != nil {
+			return nil, err
+		}
+
+		filename := fmt.Sprintf("environment-%s-branch_policies-page_%d.json", environmentDumpName(env), page)
+		if err := dumpManager.WriteJSONFile(filename, policies); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		branchPolicies = append(branchPolicies, policies.BranchPolicies...)
+
+		// Follow the Link header, falling back to total_count for servers that leave it out.
+		if resp.NextPage == 0 && (len(policies.BranchPolicies) == 0 || len(branchPolicies) >= policies.GetTotalCount()) {
+			break
+		}
+	}
+
+	return branchPolicies, nil
+}
+
+func resolveEnvironment(env *github.Environment, branchPolicies []*github.DeploymentBranchPolicy) Environment {
+	environment := Environment{
+		Name:            env.GetName(),
+		WaitTimer:       env.WaitTimer,
+		CanAdminsBypass: env.CanAdminsBypass,
+	}
+
+	for _, rule := range env.ProtectionRules {
+		switch rule.GetType() {
+		case "wait_timer":
+			environment.WaitTimer = rule.WaitTimer
+		case "required_reviewers":
+			environment.PreventSelfReview = rule.PreventSelfReview
+			environment.Reviewers = resolveEnvironmentReviewers(rule.Reviewers)
+		}
+	}
+
+	if policy := env.GetDeploymentBranchPolicy(); policy != nil {
+		environment.DeploymentBranchPolicy = &DeploymentBranchPolicy{
+			ProtectedBranches:    policy.GetProtectedBranches(),
+			CustomBranchPolicies: policy.GetCustomBranchPolicies(),
+		}
+		for _, branchPolicy := range branchPolicies {
+			switch branchPolicy.GetType() {
+			case "tag":
+				environment.DeploymentBranchPolicy.TagPatterns = append(environment.DeploymentBranchPolicy.TagPatterns, branchPolicy.GetName())
+			default:
+				environment.DeploymentBranchPolicy.BranchPatterns = append(environment.DeploymentBranchPolicy.BranchPatterns, branchPolicy.GetName())
+			}
+		}
+	}
+
+	return environment
+}
+
+func resolveEnvironmentReviewers(reviewers []*github.RequiredReviewer) *EnvironmentReviewers {
+	if len(reviewers) == 0 {
+		return nil
+	}
+
+	var result EnvironmentReviewers
+	for _, reviewer := range reviewers {
+		switch r := reviewer.Reviewer.(type) {
+		case *github.User:
+			result.Users = append(result.Users, r.GetLogin())
+		case *github.Team:
+			result.Teams = append(result.Teams, r.GetSlug())
+		default:
+			fmt.Printf("unknown environment reviewer type: %s\n", reviewer.GetType())
+		}
+	}
+	return &result
+}
+
+// environmentDumpName makes an environment name safe to use in a dump file name.
+func environmentDumpName(name string) string {
+	return strings.ReplaceAll(name, "/", "_")
+}
@@ -0,0 +1,80 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+func TestCollectEnvironmentsPaginatesDeploymentBranchPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		linkPage bool
+	}{
+		{name: "link header", linkPage: true},
+		{name: "total count without link header", linkPage: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v3/repos/acme/api/environments":
+					_, _ = w.Write([]byte(`{"total_count":1,"environments":[{"name":"prod/eu",
+						"deployment_branch_policy":{"protected_branches":false,"custom_branch_policies":true}}]}`))
+				case "/api/v3/repos/acme/api/environments/prod/eu/deployment-branch-policies":
+					assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+					switch r.URL.Query().Get("page") {
+					case "1":
+						if tt.linkPage {
+							w.Header().Set("Link", fmt.Sprintf(`<%s%s?per_page=100&page=2>; rel="next"`, server.URL, r.URL.Path))
+						}
+						_, _ = w.Write([]byte(`{"total_count":3,"branch_policies":[{"name":"main"},{"name":"release/*"}]}`))
+					case "2":
+						_, _ = w.Write([]byte(`{"total_count":3,"branch_policies":[{"name":"v*","type":"tag"}]}`))
+					default:
+						t.Errorf("unexpected page %q", r.URL.Query().Get("page"))
+					}
+				default:
+					t.Errorf("unexpected request %s", r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+			require.NoError(t, err)
+
+			dumpDir := t.TempDir()
+			dumpManager, err := file.NewDumpManager(dumpDir, "acme/api")
+			require.NoError(t, err)
+
+			environments, err := CollectEnvironments(client, "acme", "api", dumpManager)
+			require.NoError(t, err)
+
+			expected := []Environment{{
+				Name: "prod/eu",
+				DeploymentBranchPolicy: &DeploymentBranchPolicy{
+					CustomBranchPolicies: true,
+					BranchPatterns:       []string{"main", "release/*"},
+					TagPatterns:          []string{"v*"},
+				},
+			}}
+			assert.Equal(t, expected, environments)
+
+			dumpReader, err := file.NewDumpReader(filepath.Join(dumpDir, "acme/api"))
+			require.NoError(t, err)
+			fromDumps, err := readEnvironmentDumps(dumpReader)
+			require.NoError(t, err)
+			assert.Equal(t, expected, fromDumps)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"errors"
+	"sync"
+)
+
+// maxConcurrentRepoFetches bounds the API calls a single repository import runs at once.
+const maxConcurrentRepoFetches = 4
+
+// fetchGroup runs independent fetches concurrently, at most limit at a time,
+// and collects the errors of the ones that failed.
+type fetchGroup struct {
+	wg  sync.WaitGroup
+	sem chan struct{}
+	mu  sync.Mutex
+	// errs holds one slot per fetch, in the order Go was called.
+	errs []error
+}
+
+func newFetchGroup(limit int) *fetchGroup {
+	if limit < 1 {
+		limit = 1
+	}
+	return &fetchGroup{sem: make(chan struct{}, limit)}
+}
+
+func (g *fetchGroup) Go(fetch func() error) {
+	g.mu.Lock()
+	idx := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		g.sem <- struct{}{}
+		defer func() { <-g.sem }()
+
+		if err := fetch(); err != nil {
+			g.mu.Lock()
+			g.errs[idx] = err
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every fetch returned and joins their errors in the order the fetches
+// were started, so the same failures always read the same.
+func (g *fetchGroup) Wait() error {
+	g.wg.Wait()
+	return errors.Join(g.errs...)
+}
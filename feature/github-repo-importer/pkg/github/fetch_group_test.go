@@ -0,0 +1,75 @@
+package github
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchGroup(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		fetches     int
+		failing     map[int]bool
+		expectedErr string
+	}{
+		{
+			name:    "all fetches succeed",
+			limit:   2,
+			fetches: 6,
+		},
+		{
+			name:        "errors of failed fetches are joined in start order",
+			limit:       5,
+			fetches:     5,
+			failing:     map[int]bool{1: true, 4: true},
+			expectedErr: "fetch 1 failed\nfetch 4 failed",
+		},
+		{
+			name:    "limit below one runs fetches one at a time",
+			limit:   0,
+			fetches: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, maxRunning, done atomic.Int32
+
+			group := newFetchGroup(tt.limit)
+			for n := 0; n < tt.fetches; n++ {
+				group.Go(func() error {
+					current := running.Add(1)
+					defer running.Add(-1)
+					for {
+						seen := maxRunning.Load()
+						if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+							break
+						}
+					}
+					// Later fetches finish first.
+					time.Sleep(time.Duration(tt.fetches-n) * 5 * time.Millisecond)
+					done.Add(1)
+
+					if tt.failing[n] {
+						return fmt.Errorf("fetch %d failed", n)
+					}
+					return nil
+				})
+			}
+			err := group.Wait()
+
+			assert.Equal(t, int32(tt.fetches), done.Load())
+			assert.LessOrEqual(t, maxRunning.Load(), int32(max(tt.limit, 1)))
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
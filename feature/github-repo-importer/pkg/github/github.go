@@ -6,10 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/google/go-github/v67/github"
 	"github.com/shurcooL/githubv4"
@@ -18,105 +18,365 @@ import (
 	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
 )
 
-var (
-	v3client *github.Client
-	v4client *githubv4.Client
-)
-
-func init() {
-	var err error
-	v3client, v4client, err = CreateGitHubClient()
-	if err != nil {
-		panic(fmt.Sprintf("Error creating GitHub client: %v", err))
-	}
+func (i *Importer) ImportRepo(repoName string, opts ImportOptions) (*Repository, error) {
+	return i.importRepo(repoName, opts, &importWarnings{})
 }
 
-func ImportRepo(repoName string) (*Repository, error) {
+// importRepo imports a single repository and records everything it could not import
+// without failing in warnings.
+func (i *Importer) importRepo(repoName string, opts ImportOptions, warnings *importWarnings) (*Repository, error) {
 	fmt.Println("Importing repository: ", repoName)
 
 	if !isValidRepoFormat(repoName) {
 		return nil, errors.New("invalid repository format. Use owner/repo")
 	}
 
-	dumpManager, err := file.NewDumpManager(repoName)
+	dumpManager, err := file.NewStagedDumpManager(i.dumpDir, repoName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new dump manager: %w", err)
 	}
+	// Dumps of a failed import are dropped, so the previous ones stay complete. After a
+	// successful import Commit has already moved them into place.
+	defer func() {
+		if err := dumpManager.Discard(); err != nil {
+			fmt.Printf("%v\n", err)
+		}
+	}()
 
 	repoNameSplit := strings.Split(repoName, "/")
-	repo, r, err := v3client.Repositories.Get(context.Background(), repoNameSplit[0], repoNameSplit[1])
+	repo, _, err := i.v3client.Repositories.Get(context.Background(), repoNameSplit[0], repoNameSplit[1])
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch repo: %w (API Response: %s)", err, r.Status)
+		return nil, fmt.Errorf("failed to fetch repo: %w", err)
 	}
 
 	if err := dumpManager.WriteJSONFile("repository.json", repo); err != nil {
-		fmt.Printf("failed to write repository.json: %v\n", err)
+		warnings.Warnf("failed to write repository.json: %v", err)
 	}
 
-	categorizedCollaborators, err := CategorizeCollaborators(v3client, repoNameSplit[0], repoNameSplit[1], dumpManager)
-	if err != nil {
-		return nil, fmt.Errorf("failed to categorize collaborators: %w", err)
-	}
+	owner, name := repoNameSplit[0], repoNameSplit[1]
 
-	categorizedTeams, err := CategorizeTeams(v3client, repoNameSplit[0], repoNameSplit[1], dumpManager)
-	if err != nil {
-		fmt.Printf("failed to categorize teams: %v\n", err)
-	}
+	// Everything below only depends on the repository fetched above, so the calls run
+	// concurrently. Each one writes its own variables and its own dump files.
+	var (
+		categorizedCollaborators   *PermissionGroups
+		categorizedTeams           = &PermissionGroups{}
+		webhooks                   []Webhook
+		deployKeys                 []DeployKey
+		issueLabels                []IssueLabel
+		autolinkReferences         []AutolinkReference
+		environments               []Environment
+		actionsSecrets             []ActionsSecret
+		actionsVariables           []ActionsVariable
+		actions                    *Actions
+		pages                      *github.Pages
+		collectedRulesets          []github.Ruleset
+		vulnerabilityAlertsEnabled bool
+		securityAndAnalysis        *SecurityAndAnalysis
+		customProperties           map[string]interface{}
+		branchProtectionRules      []BranchProtectionRuleNode
+	)
 
-	pages, r, err := v3client.Repositories.GetPagesInfo(context.Background(), repoNameSplit[0], repoNameSplit[1])
-	if err != nil {
-		fmt.Printf("failed to get pages info: %v\n", err)
-	}
+	group := newFetchGroup(maxConcurrentRepoFetches)
 
-	if err := dumpManager.WriteJSONFile("pages.json", pages); err != nil {
-		fmt.Printf("failed to write pages.json: %v\n", err)
-	}
+	group.Go(func() error {
+		var err error
+		if categorizedCollaborators, err = CategorizeCollaborators(i.v3client, owner, name, dumpManager); err != nil {
+			return fmt.Errorf("failed to categorize collaborators: %w", err)
+		}
+		return nil
+	})
 
-	rulesets, r, err := v3client.Repositories.GetAllRulesets(context.Background(), repoNameSplit[0], repoNameSplit[1], false)
-	if err != nil {
-		if r.StatusCode == http.StatusForbidden {
-			fmt.Printf("skipping rulesets due to insufficient permissions: %v\n", err)
-		} else {
-			return nil, fmt.Errorf("failed to get all rulesets: %v", err)
+	group.Go(func() error {
+		teams, err := CategorizeTeams(i.v3client, owner, name, dumpManager)
+		if err != nil {
+			warnings.Warnf("failed to categorize teams: %v", err)
+			return nil
 		}
-	}
+		categorizedTeams = teams
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if webhooks, err = CollectWebhooks(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect webhooks: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if deployKeys, err = CollectDeployKeys(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect deploy keys: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if issueLabels, err = CollectIssueLabels(i.v3client, owner, name, opts.ExcludeDefaultLabels, dumpManager); err != nil {
+			warnings.Warnf("failed to collect issue labels: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if autolinkReferences, err = CollectAutolinkReferences(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect autolink references: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if environments, err = CollectEnvironments(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect environments: %v", err)
+		}
+
+		for idx := range environments {
+			envName := environments[idx].Name
+			if environments[idx].Secrets, err = CollectEnvironmentSecrets(i.v3client, repo.GetID(), envName, dumpManager); err != nil {
+				warnings.Warnf("failed to collect secrets of environment %q: %v", envName, err)
+			}
+			if environments[idx].Variables, err = CollectEnvironmentVariables(i.v3client, owner, name, envName, dumpManager); err != nil {
+				warnings.Warnf("failed to collect variables of environment %q: %v", envName, err)
+			}
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if actionsSecrets, err = CollectActionsSecrets(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect actions secrets: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if actionsVariables, err = CollectActionsVariables(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect actions variables: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if actions, err = CollectActions(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect actions permissions: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		pages, _, err = i.v3client.Repositories.GetPagesInfo(context.Background(), owner, name)
+		if err != nil {
+			warnings.Warnf("failed to get pages info: %v", err)
+		}
+
+		if err := dumpManager.WriteJSONFile("pages.json", pages); err != nil {
+			warnings.Warnf("failed to write pages.json: %v", err)
+		}
+		return nil
+	})
 
-	var collectedRulesets []github.Ruleset
-	for _, ruleset := range rulesets {
-		rulesetById, _, _ := v3client.Repositories.GetRuleset(context.Background(), repoNameSplit[0], repoNameSplit[1], ruleset.GetID(), false)
+	group.Go(func() error {
+		rulesets, r, err := i.v3client.Repositories.GetAllRulesets(context.Background(), owner, name, false)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get rullset %v: %w", ruleset, err)
+			if r != nil && r.StatusCode == http.StatusForbidden {
+				warnings.Warnf("skipping rulesets due to insufficient permissions: %v", err)
+			} else if isUnsupported(r) {
+				warnings.Warnf("skipping rulesets, not supported by the server: %v", err)
+			} else {
+				return fmt.Errorf("failed to get all rulesets: %v", err)
+			}
+		}
+
+		for _, ruleset := range rulesets {
+			rulesetById, _, err := i.v3client.Repositories.GetRuleset(context.Background(), owner, name, ruleset.GetID(), false)
+			if err != nil {
+				return fmt.Errorf("failed to get rullset %v: %w", ruleset, err)
+			}
+			collectedRulesets = append(collectedRulesets, *rulesetById)
+			filename := fmt.Sprintf("ruleset%d.json", rulesetById.GetID())
+			if err := dumpManager.WriteJSONFile(filename, rulesetById); err != nil {
+				warnings.Warnf("failed to write json file %q: %v", filename, err)
+			}
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if vulnerabilityAlertsEnabled, _, err = i.v3client.Repositories.GetVulnerabilityAlerts(context.Background(), owner, name); err != nil {
+			return fmt.Errorf("failed to fetch vulnerability alerts: %v\n", err)
+		}
+
+		if err := dumpManager.WriteJSONFile("vulnerability_alerts.json", map[string]bool{"enabled": vulnerabilityAlertsEnabled}); err != nil {
+			warnings.Warnf("failed to write vulnerability_alerts.json: %v", err)
 		}
-		collectedRulesets = append(collectedRulesets, *rulesetById)
-		filename := fmt.Sprintf("ruleset%d.json", rulesetById.GetID())
-		if err := dumpManager.WriteJSONFile(filename, rulesetById); err != nil {
-			fmt.Printf("failed to write json file %q: %v\n", filename, err)
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if securityAndAnalysis, err = CollectSecurityAndAnalysis(i.v3client, repo, dumpManager); err != nil {
+			warnings.Warnf("failed to collect security and analysis settings: %v", err)
+		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if customProperties, err = CollectCustomProperties(i.v3client, owner, name, dumpManager); err != nil {
+			warnings.Warnf("failed to collect custom properties: %v", err)
 		}
+		return nil
+	})
+
+	group.Go(func() error {
+		var err error
+		if branchProtectionRules, err = CollectBranchProtectionRules(i.v4client, owner, name, dumpManager); err != nil {
+			// A partial list would drop rules on the next apply, while a query that fails
+			// outright, e.g. for lack of permissions, only leaves the rules unmanaged.
+			if errors.Is(err, errBranchProtectionRulesTruncated) {
+				return fmt.Errorf("failed to fetch branch protection rules: %w", err)
+			}
+			warnings.Warnf("skipping branch protection rules: %v", err)
+		}
+		return nil
+	})
+
+	if err := group.Wait(); err != nil {
+		return nil, err
 	}
 
-	vulnerabilityAlertsEnabled, r, err := v3client.Repositories.GetVulnerabilityAlerts(context.Background(), repoNameSplit[0], repoNameSplit[1])
+	resolvedRulesets, err := resolveRulesets(collectedRulesets)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch vulnerability alerts: %v\n", err)
+		warnings.Warnf("failed to resolve rulesets: %v", err)
 	}
 
-	vars := map[string]interface{}{
-		"owner": githubv4.String(repoNameSplit[0]),
-		"name":  githubv4.String(repoNameSplit[1]),
+	if err := dumpManager.Commit(); err != nil {
+		warnings.Warnf("failed to write dumps: %v", err)
 	}
 
-	var branchProtectionRulesGraphQLQuery BranchProtectionRulesGraphQLQuery
-	err = v4client.Query(context.Background(), &branchProtectionRulesGraphQLQuery, vars)
-	if err != nil {
-		fmt.Printf("Failed to fetch branch protection rules: %v\n", err)
+	return newRepository(repo, repositorySettings{
+		collaborators:              categorizedCollaborators,
+		teams:                      categorizedTeams,
+		pages:                      pages,
+		webhooks:                   webhooks,
+		deployKeys:                 deployKeys,
+		issueLabels:                issueLabels,
+		autolinkReferences:         autolinkReferences,
+		environments:               environments,
+		actions:                    actions,
+		actionsSecrets:             actionsSecrets,
+		actionsVariables:           actionsVariables,
+		rulesets:                   resolvedRulesets,
+		vulnerabilityAlertsEnabled: &vulnerabilityAlertsEnabled,
+		securityAndAnalysis:        securityAndAnalysis,
+		customProperties:           customProperties,
+		branchProtectionRules:      branchProtectionRules,
+	}), nil
+}
+
+// listOrgRepos returns the repositories of an organization to import and records the
+// skipped ones in the report.
+func (i *Importer) listOrgRepos(org OrganizationConfig, pageSize int, bulkOpts BulkImportOptions, report *ImportReport) ([]string, error) {
+	// If selectedRepos list has items, we don't fetch all repos via API, but jump to fetching one by one
+	if len(org.SelectedRepos) > 0 {
+		return org.SelectedRepos, nil
 	}
 
-	if err := dumpManager.WriteJSONFile("branch_protection_rules-graphql.json", branchProtectionRulesGraphQLQuery); err != nil {
-		fmt.Printf("failed to write branch_protection_rules.json: %v\n", err)
+	var auditLogRepos map[string]bool
+	if !bulkOpts.Since.IsZero() && bulkOpts.UseAuditLog {
+		var err error
+		if auditLogRepos, err = i.CollectAuditLogRepos(org.Name, bulkOpts.Since); err != nil {
+			fmt.Printf("failed to read the audit log, relying on repository timestamps only: %v\n", err)
+		}
 	}
 
-	resolvedRulesets, err := resolveRulesets(collectedRulesets)
+	selector, err := i.newRepoSelector(org)
 	if err != nil {
-		fmt.Printf("failed to resolve rulesets: %v\n", err)
+		return nil, err
+	}
+
+	var reposToImport []string
+	opts := &github.RepositoryListByOrgOptions{
+		ListOptions: github.ListOptions{PerPage: pageSize},
+	}
+	for {
+		ghRepositories, r, err := i.v3client.Repositories.ListByOrg(context.Background(), org.Name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch repos: %w", err)
+		}
+
+		for _, ghRepo := range ghRepositories {
+
+			if slices.Contains(org.IgnoredRepos, ghRepo.GetFullName()) {
+				fmt.Printf("skipping ignored repository %s\n", ghRepo.GetFullName())
+				report.SkippedIgnored = append(report.SkippedIgnored, ghRepo.GetFullName())
+				continue
+			}
+
+			if reason := selector.skipReason(ghRepo); reason == "archived" {
+				fmt.Printf("skipping archived repository %s\n", ghRepo.GetFullName())
+				report.SkippedArchived = append(report.SkippedArchived, ghRepo.GetFullName())
+				continue
+			} else if reason != "" {
+				fmt.Printf("skipping filtered repository %s: %s\n", ghRepo.GetFullName(), reason)
+				report.SkippedFiltered = append(report.SkippedFiltered, FilteredRepo{Repository: ghRepo.GetFullName(), Reason: reason})
+				continue
+			}
+
+			if !bulkOpts.Since.IsZero() && !isChangedSince(ghRepo, bulkOpts.Since) && !auditLogRepos[ghRepo.GetFullName()] {
+				report.SkippedUnchanged = append(report.SkippedUnchanged, ghRepo.GetFullName())
+				continue
+			}
+
+			reposToImport = append(reposToImport, ghRepo.GetFullName())
+		}
+
+		if r.NextPage == 0 {
+			break
+		}
+
+		opts.Page = r.NextPage
+	}
+
+	return reposToImport, nil
+}
+
+// repositorySettings holds everything imported besides the repository payload itself,
+// whether it was fetched from GitHub or read back from dumps.
+type repositorySettings struct {
+	collaborators              *PermissionGroups
+	teams                      *PermissionGroups
+	pages                      *github.Pages
+	webhooks                   []Webhook
+	deployKeys                 []DeployKey
+	issueLabels                []IssueLabel
+	autolinkReferences         []AutolinkReference
+	environments               []Environment
+	actions                    *Actions
+	actionsSecrets             []ActionsSecret
+	actionsVariables           []ActionsVariable
+	rulesets                   []Ruleset
+	vulnerabilityAlertsEnabled *bool
+	securityAndAnalysis        *SecurityAndAnalysis
+	customProperties           map[string]interface{}
+	branchProtectionRules      []BranchProtectionRuleNode
+}
+
+func newRepository(repo *github.Repository, settings repositorySettings) *Repository {
+	if settings.collaborators == nil {
+		settings.collaborators = &PermissionGroups{}
+	}
+	if settings.teams == nil {
+		settings.teams = &PermissionGroups{}
 	}
 
 	return &Repository{
@@ -145,82 +405,136 @@ func ImportRepo(repoName string) (*Repository, error) {
 		HasDiscussions:             repo.HasDiscussions,
 		Archived:                   repo.Archived,
 		Topics:                     repo.Topics,
-		PullCollaborators:          categorizedCollaborators.Pull,
-		TriageCollaborators:        categorizedCollaborators.Triage,
-		PushCollaborators:          categorizedCollaborators.Push,
-		MaintainCollaborators:      categorizedCollaborators.Maintain,
-		AdminCollaborators:         categorizedCollaborators.Admin,
-		PullTeams:                  categorizedTeams.Pull,
-		TriageTeams:                categorizedTeams.Triage,
-		PushTeams:                  categorizedTeams.Push,
-		MaintainTeams:              categorizedTeams.Maintain,
-		AdminTeams:                 categorizedTeams.Admin,
+		PullCollaborators:          settings.collaborators.Pull,
+		TriageCollaborators:        settings.collaborators.Triage,
+		PushCollaborators:          settings.collaborators.Push,
+		MaintainCollaborators:      settings.collaborators.Maintain,
+		AdminCollaborators:         settings.collaborators.Admin,
+		PullTeams:                  settings.teams.Pull,
+		TriageTeams:                settings.teams.Triage,
+		PushTeams:                  settings.teams.Push,
+		MaintainTeams:              settings.teams.Maintain,
+		AdminTeams:                 settings.teams.Admin,
 		LicenseTemplate:            repo.LicenseTemplate,
 		GitignoreTemplate:          repo.GitignoreTemplate,
 		Template:                   resolveRepositoryTemplate(repo),
-		Pages:                      resolvePages(pages),
-		Rulesets:                   resolvedRulesets,
-		VulnerabilityAlertsEnabled: &vulnerabilityAlertsEnabled,
-		BranchProtectionsV4:        resolveBranchProtectionsFromGraphQL(&branchProtectionRulesGraphQLQuery),
-	}, nil
+		Pages:                      resolvePages(settings.pages),
+		Webhooks:                   settings.webhooks,
+		DeployKeys:                 settings.deployKeys,
+		IssueLabels:                settings.issueLabels,
+		AutolinkReferences:         settings.autolinkReferences,
+		Environments:               settings.environments,
+		Actions:                    settings.actions,
+		ActionsSecrets:             settings.actionsSecrets,
+		ActionsVariables:           settings.actionsVariables,
+		Rulesets:                   settings.rulesets,
+		VulnerabilityAlertsEnabled: settings.vulnerabilityAlertsEnabled,
+		SecurityAndAnalysis:        settings.securityAndAnalysis,
+		CustomProperties:           settings.customProperties,
+		BranchProtectionsV4:        resolveBranchProtectionsFromGraphQL(settings.branchProtectionRules),
+	}
 }
 
-func ImportRepos(cfg Config) ([]*Repository, error) {
-	reposToImport := cfg.SelectedRepos
+// ImportRepos imports every repository the configuration selects, across all its organizations.
+// A repository that fails to import does not stop the others: it is recorded in the report,
+// which also lists the skipped repositories and the warnings of the imported ones. The returned
+// error is only set when the configuration is unusable or the repositories could not be listed
+// at all.
+func (i *Importer) ImportRepos(cfg Config, bulkOpts BulkImportOptions) ([]*Repository, *ImportReport, error) {
+	// Without a worker, sending the first repository would block forever.
+	if cfg.Concurrency != nil && *cfg.Concurrency < 1 {
+		return nil, nil, errors.New("concurrency must be at least 1")
+	}
 
-	// If selectedRepos list has items, we don't fetch all repos via API, but jump to fetching one by one
-	if len(reposToImport) == 0 {
-		opts := &github.RepositoryListByOrgOptions{
-			ListOptions: github.ListOptions{PerPage: *cfg.PageSize},
+	orgs, err := cfg.ResolveOrganizations(bulkOpts.Owner)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageSize := DefaultPageSize
+	if cfg.PageSize != nil {
+		pageSize = *cfg.PageSize
+	}
+
+	report := NewImportReport()
+	var reposToImport []string
+	for _, org := range orgs {
+		orgRepos, err := i.listOrgRepos(org, pageSize, bulkOpts, report)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list repositories of %s: %w", org.Name, err)
 		}
-		for {
-			ghRepositories, r, err := v3client.Repositories.ListByOrg(context.Background(), os.Getenv("OWNER"), opts)
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch repos: %w (API Response: %s)", err, r.Status)
+		reposToImport = append(reposToImport, orgRepos...)
+	}
+
+	if bulkOpts.Skip != nil {
+		reposToImport = slices.DeleteFunc(slices.Clone(reposToImport), func(repoName string) bool {
+			if !bulkOpts.Skip(repoName) {
+				return false
 			}
+			fmt.Printf("skipping already imported repository %s\n", repoName)
+			report.SkippedCompleted = append(report.SkippedCompleted, repoName)
+			return true
+		})
+	}
 
-			for _, ghRepo := range ghRepositories {
+	importOpts := ImportOptions{
+		ExcludeDefaultLabels: cfg.ExcludeDefaultLabels != nil && *cfg.ExcludeDefaultLabels,
+	}
 
-				if slices.Contains(cfg.IgnoredRepos, *ghRepo.FullName) {
-					fmt.Printf("skipping ignored repository %s\n", *ghRepo.FullName)
-					continue
-				}
+	concurrency := DefaultConcurrency
+	if cfg.Concurrency != nil {
+		concurrency = *cfg.Concurrency
+	}
 
-				if ghRepo.GetArchived() {
-					fmt.Printf("skipping archived repository %s\n", *ghRepo.FullName)
-					continue
+	// Workers write into the slot of their repository, so the result keeps the order of reposToImport
+	// no matter which import finishes first.
+	importedRepos := make([]*Repository, len(reposToImport))
+	importErrs := make([]error, len(reposToImport))
+	importWarns := make([]importWarnings, len(reposToImport))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(concurrency, len(reposToImport)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				importedRepos[idx], importErrs[idx] = i.importRepo(reposToImport[idx], importOpts, &importWarns[idx])
+				if importErrs[idx] == nil && bulkOpts.OnImported != nil {
+					importErrs[idx] = bulkOpts.OnImported(reposToImport[idx], importedRepos[idx])
 				}
-
-				reposToImport = append(reposToImport, *ghRepo.FullName)
 			}
+		}()
+	}
 
-			if r.NextPage == 0 {
-				break
-			}
+	for idx := range reposToImport {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
 
-			opts.Page = r.NextPage
+	var succeeded []*Repository
+	for idx, repoName := range reposToImport {
+		for _, message := range importWarns[idx].sorted() {
+			report.Warnings = append(report.Warnings, ImportWarning{Repository: repoName, Message: message})
 		}
-	}
 
-	var importedRepos []*Repository
-	for _, repoToImport := range reposToImport {
-		repository, err := ImportRepo(repoToImport)
-		if err != nil {
-			return nil, fmt.Errorf("failed to import repository %s: %w", repository.Name, err)
+		if err := importErrs[idx]; err != nil {
+			fmt.Printf("failed to import repository %s: %v\n", repoName, err)
+			report.Failed = append(report.Failed, FailedImport{Repository: repoName, Reason: err.Error()})
+			continue
 		}
-		importedRepos = append(importedRepos, repository)
-	}
-	return importedRepos, nil
-}
 
-func resolveBranchProtectionsFromGraphQL(query *BranchProtectionRulesGraphQLQuery) []*BranchProtectionV4 {
-	if query == nil {
-		return nil
+		report.Succeeded = append(report.Succeeded, repoName)
+		succeeded = append(succeeded, importedRepos[idx])
 	}
+	return succeeded, report, nil
+}
 
+func resolveBranchProtectionsFromGraphQL(nodes []BranchProtectionRuleNode) []*BranchProtectionV4 {
 	var rules []*BranchProtectionV4
 
-	for _, rule := range query.Repository.BranchProtectionRules.Nodes {
+	for _, rule := range nodes {
 		var requiredPullRequestReviews *RequiredPullRequestReviews
 		if anyTrue(rule.RequiredApprovingReviewCount != nil,
 			rule.DismissesStaleReviews,
@@ -293,6 +607,8 @@ func resolveActors(nodes []ActorWrapper) []string {
 			actors = append(actors, string(node.Actor.Team.Name))
 		case node.Actor.App.Name != "":
 			actors = append(actors, "app/"+string(node.Actor.App.Name))
+		default:
+			fmt.Printf("warning: skipping allowance actor of unsupported type: %+v\n", node.Actor)
 		}
 	}
 	return actors
@@ -584,13 +900,7 @@ type PermissionGroups struct {
 }
 
 func CategorizeCollaborators(client *github.Client, owner, repo string, dumpManager *file.DumpManager) (*PermissionGroups, error) {
-	var (
-		pullCollaborators     []string
-		triageCollaborators   []string
-		pushCollaborators     []string
-		maintainCollaborators []string
-		adminCollaborators    []string
-	)
+	var collaborators []*github.User
 
 	opts := &github.ListCollaboratorsOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
@@ -598,13 +908,13 @@ func CategorizeCollaborators(client *github.Client, owner, repo string, dumpMana
 	}
 
 	for {
-		collaborators, resp, err := client.Repositories.ListCollaborators(context.Background(), owner, repo, opts)
+		pageCollaborators, resp, err := client.Repositories.ListCollaborators(context.Background(), owner, repo, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch collaborators: %w", err)
 		}
 
 		filename := fmt.Sprintf("collaborators-page_%d.json", opts.Page+1)
-		if err := dumpManager.WriteJSONFile(filename, collaborators); err != nil {
+		if err := dumpManager.WriteJSONFile(filename, pageCollaborators); err != nil {
 			fmt.Printf("failed to write %q: %v\n", filename, err)
 		}
 
@@ -612,24 +922,7 @@ func CategorizeCollaborators(client *github.Client, owner, repo string, dumpMana
 			return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
 		}
 
-		for _, collaborator := range collaborators {
-			roleName := collaborator.GetRoleName()
-
-			switch roleName {
-			case PermissionRead:
-				pullCollaborators = append(pullCollaborators, collaborator.GetLogin())
-			case PermissionAdmin:
-				adminCollaborators = append(adminCollaborators, collaborator.GetLogin())
-			case PermissionTriage:
-				triageCollaborators = append(triageCollaborators, collaborator.GetLogin())
-			case PermissionPush, PermissionWrite:
-				pushCollaborators = append(pushCollaborators, collaborator.GetLogin())
-			case PermissionMaintain:
-				maintainCollaborators = append(maintainCollaborators, collaborator.GetLogin())
-			default:
-				fmt.Printf("unknown role name: %s\n", roleName)
-			}
-		}
+		collaborators = append(collaborators, pageCollaborators...)
 
 		if resp.NextPage == 0 {
 			break
@@ -638,34 +931,59 @@ func CategorizeCollaborators(client *github.Client, owner, repo string, dumpMana
 		opts.Page = resp.NextPage
 	}
 
+	return resolveCollaborators(collaborators), nil
+}
+
+func resolveCollaborators(collaborators []*github.User) *PermissionGroups {
+	var (
+		pullCollaborators     []string
+		triageCollaborators   []string
+		pushCollaborators     []string
+		maintainCollaborators []string
+		adminCollaborators    []string
+	)
+
+	for _, collaborator := range collaborators {
+		roleName := collaborator.GetRoleName()
+
+		switch roleName {
+		case PermissionRead:
+			pullCollaborators = append(pullCollaborators, collaborator.GetLogin())
+		case PermissionAdmin:
+			adminCollaborators = append(adminCollaborators, collaborator.GetLogin())
+		case PermissionTriage:
+			triageCollaborators = append(triageCollaborators, collaborator.GetLogin())
+		case PermissionPush, PermissionWrite:
+			pushCollaborators = append(pushCollaborators, collaborator.GetLogin())
+		case PermissionMaintain:
+			maintainCollaborators = append(maintainCollaborators, collaborator.GetLogin())
+		default:
+			fmt.Printf("unknown role name: %s\n", roleName)
+		}
+	}
+
 	return &PermissionGroups{
 		Pull:     pullCollaborators,
 		Triage:   triageCollaborators,
 		Push:     pushCollaborators,
 		Maintain: maintainCollaborators,
 		Admin:    adminCollaborators,
-	}, nil
+	}
 }
 
 func CategorizeTeams(client *github.Client, owner, repo string, dumpManager *file.DumpManager) (*PermissionGroups, error) {
-	var (
-		pullTeams     []string
-		triageTeams   []string
-		pushTeams     []string
-		maintainTeams []string
-		adminTeams    []string
-	)
+	var teams []*github.Team
 
 	opts := &github.ListOptions{PerPage: 100}
 
 	for {
-		teams, resp, err := client.Repositories.ListTeams(context.Background(), owner, repo, opts)
+		pageTeams, resp, err := client.Repositories.ListTeams(context.Background(), owner, repo, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to list teams: %w", err)
 		}
 
 		filename := fmt.Sprintf("teams-page_%d.json", opts.Page+1)
-		if err := dumpManager.WriteJSONFile(filename, teams); err != nil {
+		if err := dumpManager.WriteJSONFile(filename, pageTeams); err != nil {
 			fmt.Printf("failed to write %q: %v\n", filename, err)
 		}
 
@@ -673,23 +991,7 @@ func CategorizeTeams(client *github.Client, owner, repo string, dumpManager *fil
 			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 		}
 
-		for _, team := range teams {
-			permission := team.GetPermission()
-			switch permission {
-			case PermissionPull:
-				pullTeams = append(pullTeams, team.GetSlug())
-			case PermissionAdmin:
-				adminTeams = append(adminTeams, team.GetSlug())
-			case PermissionTriage:
-				triageTeams = append(triageTeams, team.GetSlug())
-			case PermissionPush, PermissionWrite:
-				pushTeams = append(pushTeams, team.GetSlug())
-			case PermissionMaintain:
-				maintainTeams = append(maintainTeams, team.GetSlug())
-			default:
-				fmt.Printf("unknown permission name: %s\n", permission)
-			}
-		}
+		teams = append(teams, pageTeams...)
 
 		if resp.NextPage == 0 {
 			break
@@ -698,27 +1000,54 @@ func CategorizeTeams(client *github.Client, owner, repo string, dumpManager *fil
 		opts.Page = resp.NextPage
 	}
 
+	return resolveTeams(teams), nil
+}
+
+func resolveTeams(teams []*github.Team) *PermissionGroups {
+	var (
+		pullTeams     []string
+		triageTeams   []string
+		pushTeams     []string
+		maintainTeams []string
+		adminTeams    []string
+	)
+
+	for _, team := range teams {
+		permission := team.GetPermission()
+		switch permission {
+		case PermissionPull:
+			pullTeams = append(pullTeams, team.GetSlug())
+		case PermissionAdmin:
+			adminTeams = append(adminTeams, team.GetSlug())
+		case PermissionTriage:
+			triageTeams = append(triageTeams, team.GetSlug())
+		case PermissionPush, PermissionWrite:
+			pushTeams = append(pushTeams, team.GetSlug())
+		case PermissionMaintain:
+			maintainTeams = append(maintainTeams, team.GetSlug())
+		default:
+			fmt.Printf("unknown permission name: %s\n", permission)
+		}
+	}
+
 	return &PermissionGroups{
 		Pull:     pullTeams,
 		Triage:   triageTeams,
 		Push:     pushTeams,
 		Maintain: maintainTeams,
 		Admin:    adminTeams,
-	}, nil
+	}
 }
 
-func WriteRepositoryToYaml(repository *Repository) error {
+// WriteRepositoryToYaml writes the repository to <outputDir>/<owner>/<name>.yaml.
+func WriteRepositoryToYaml(outputDir string, repository *Repository) error {
 	data, err := yaml.Marshal(repository)
 	if err != nil {
 		return fmt.Errorf("failed to marshal repository to YAML: %w", err)
 	}
 
-	configsBasePath := fmt.Sprintf("./configs/%s", repository.Owner)
-	if err := os.MkdirAll(configsBasePath, os.ModePerm); err != nil {
-		return fmt.Errorf("failed to create base directories: %w", err)
-	}
-
-	if err := os.WriteFile(filepath.Join(configsBasePath, fmt.Sprintf("%s.yaml", repository.Name)), data, os.ModePerm); err != nil {
+	path := filepath.Join(outputDir, repository.Owner, fmt.Sprintf("%s.yaml", repository.Name))
+	if err := file.WriteFileAtomic(path, data); err != nil {
 		return fmt.Errorf("failed to write repository to YAML: %w", err)
 	}
 
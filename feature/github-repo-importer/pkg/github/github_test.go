@@ -3,6 +3,7 @@ package github
 import (
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v67/github"
 
@@ -154,7 +155,7 @@ func TestImportRepo(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo, err := ImportRepo(tt.repoName)
+			repo, err := NewImporter(nil, nil).ImportRepo(tt.repoName, ImportOptions{})
 
 			if tt.wantError {
 				assert.Error(t, err)
@@ -203,3 +204,332 @@ func TestResolvePages(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveWebhooks(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []*github.Hook
+		expected []Webhook
+	}{
+		{
+			name: "converts hook config and drops secret",
+			input: []*github.Hook{
+				{
+					Events: []string{"push", "pull_request"},
+					Active: github.Bool(true),
+					Config: &github.HookConfig{
+						URL:         github.String("https://example.com/hook"),
+						ContentType: github.String("json"),
+						InsecureSSL: github.String("1"),
+						Secret:      github.String("super-secret"),
+					},
+				},
+			},
+			expected: []Webhook{
+				{
+					URL:         "https://example.com/hook",
+					ContentType: github.String("json"),
+					Events:      []string{"push", "pull_request"},
+					Active:      github.Bool(true),
+					InsecureSSL: github.Bool(true),
+				},
+			},
+		},
+		{
+			name:     "skips hooks without config",
+			input:    []*github.Hook{{Events: []string{"push"}}},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redactHookSecrets(tt.input)
+			for _, hook := range tt.input {
+				if hook.Config != nil && hook.Config.Secret != nil {
+					assert.Equal(t, redactedValue, hook.Config.GetSecret())
+				}
+			}
+
+			result := resolveWebhooks(tt.input)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestResolveDeployKeys(t *testing.T) {
+	input := []*github.Key{
+		{
+			ID:       github.Int64(1),
+			Title:    github.String("CI mirror"),
+			Key:      github.String("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5"),
+			ReadOnly: github.Bool(true),
+		},
+		nil,
+	}
+
+	expected := []DeployKey{
+		{
+			Title:    "CI mirror",
+			Key:      "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5",
+			ReadOnly: github.Bool(true),
+		},
+	}
+
+	assert.Equal(t, expected, resolveDeployKeys(input))
+}
+
+func TestResolveIssueLabels(t *testing.T) {
+	input := []*github.Label{
+		{Name: github.String("bug"), Color: github.String("d73a4a"), Description: github.String("Something isn't working"), Default: github.Bool(true)},
+		{Name: github.String("wontfix"), Color: github.String("000000"), Description: github.String("This will not be worked on"), Default: github.Bool(true)},
+		{Name: github.String("needs-triage"), Color: github.String("fbca04")},
+	}
+
+	tests := []struct {
+		name            string
+		excludeDefaults bool
+		expected        []string
+	}{
+		{
+			name:            "keeps all labels",
+			excludeDefaults: false,
+			expected:        []string{"bug", "wontfix", "needs-triage"},
+		},
+		{
+			name:            "drops unmodified default labels",
+			excludeDefaults: true,
+			expected:        []string{"wontfix", "needs-triage"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, label := range resolveIssueLabels(input, tt.excludeDefaults) {
+				names = append(names, label.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestResolveAutolinkReferences(t *testing.T) {
+	input := []*github.Autolink{
+		{
+			ID:             github.Int64(1),
+			KeyPrefix:      github.String("JIRA-"),
+			URLTemplate:    github.String("https://jira.example.com/browse/JIRA-<num>"),
+			IsAlphanumeric: github.Bool(false),
+		},
+	}
+
+	expected := []AutolinkReference{
+		{
+			KeyPrefix:         "JIRA-",
+			TargetURLTemplate: "https://jira.example.com/browse/JIRA-<num>",
+			IsAlphanumeric:    github.Bool(false),
+		},
+	}
+
+	assert.Equal(t, expected, resolveAutolinkReferences(input))
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	env := &github.Environment{
+		Name:            github.String("prod"),
+		CanAdminsBypass: github.Bool(false),
+		ProtectionRules: []*github.ProtectionRule{
+			{
+				Type:      github.String("wait_timer"),
+				WaitTimer: github.Int(30),
+			},
+			{
+				Type:              github.String("required_reviewers"),
+				PreventSelfReview: github.Bool(true),
+				Reviewers: []*github.RequiredReviewer{
+					{Type: github.String("User"), Reviewer: &github.User{Login: github.String("octocat")}},
+					{Type: github.String("Team"), Reviewer: &github.Team{Slug: github.String("release-managers")}},
+				},
+			},
+		},
+		DeploymentBranchPolicy: &github.BranchPolicy{
+			ProtectedBranches:    github.Bool(false),
+			CustomBranchPolicies: github.Bool(true),
+		},
+	}
+	branchPolicies := []*github.DeploymentBranchPolicy{
+		{Name: github.String("release/*"), Type: github.String("branch")},
+		{Name: github.String("v*"), Type: github.String("tag")},
+	}
+
+	expected := Environment{
+		Name:              "prod",
+		WaitTimer:         github.Int(30),
+		CanAdminsBypass:   github.Bool(false),
+		PreventSelfReview: github.Bool(true),
+		Reviewers: &EnvironmentReviewers{
+			Users: []string{"octocat"},
+			Teams: []string{"release-managers"},
+		},
+		DeploymentBranchPolicy: &DeploymentBranchPolicy{
+			ProtectedBranches:    false,
+			CustomBranchPolicies: true,
+			BranchPatterns:       []string{"release/*"},
+			TagPatterns:          []string{"v*"},
+		},
+	}
+
+	assert.Equal(t, expected, resolveEnvironment(env, branchPolicies))
+}
+
+func TestResolveActions(t *testing.T) {
+	tests := []struct {
+		name                string
+		permissions         *github.ActionsPermissionsRepository
+		allowed             *github.ActionsAllowed
+		workflowPermissions *github.DefaultWorkflowPermissionRepository
+		expected            *Actions
+	}{
+		{
+			name: "selected actions with patterns",
+			permissions: &github.ActionsPermissionsRepository{
+				Enabled:        github.Bool(true),
+				AllowedActions: github.String(AllowedActionsSelected),
+			},
+			allowed: &github.ActionsAllowed{
+				GithubOwnedAllowed: github.Bool(true),
+				VerifiedAllowed:    github.Bool(false),
+				PatternsAllowed:    []string{"G-Research/*"},
+			},
+			workflowPermissions: &github.DefaultWorkflowPermissionRepository{
+				DefaultWorkflowPermissions:   github.String("read"),
+				CanApprovePullRequestReviews: github.Bool(false),
+			},
+			expected: &Actions{
+				Enabled:        github.Bool(true),
+				AllowedActions: github.String(AllowedActionsSelected),
+				AllowedActionsConfig: &AllowedActionsConfig{
+					GithubOwnedAllowed: github.Bool(true),
+					VerifiedAllowed:    github.Bool(false),
+					PatternsAllowed:    []string{"G-Research/*"},
+				},
+				DefaultWorkflowPermissions:   github.String("read"),
+				CanApprovePullRequestReviews: github.Bool(false),
+			},
+		},
+		{
+			name:        "actions disabled",
+			permissions: &github.ActionsPermissionsRepository{Enabled: github.Bool(false)},
+			expected:    &Actions{Enabled: github.Bool(false)},
+		},
+		{
+			name:     "nil permissions",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveActions(tt.permissions, tt.allowed, tt.workflowPermissions))
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	updatedAt := time.Date(2024, 11, 5, 10, 30, 0, 0, time.UTC)
+	input := &github.Secrets{
+		TotalCount: 2,
+		Secrets: []*github.Secret{
+			{Name: "DEPLOY_TOKEN", UpdatedAt: github.Timestamp{Time: updatedAt}},
+			{Name: "NEVER_UPDATED"},
+		},
+	}
+
+	expected := []ActionsSecret{
+		{Name: "DEPLOY_TOKEN", UpdatedAt: "2024-11-05T10:30:00Z"},
+		{Name: "NEVER_UPDATED"},
+	}
+
+	assert.Equal(t, expected, resolveSecrets(input))
+	assert.Nil(t, resolveSecrets(nil))
+}
+
+func TestResolveSecurityAndAnalysis(t *testing.T) {
+	tests := []struct {
+		name                    string
+		input                   *github.SecurityAndAnalysis
+		automatedSecurityFixes  *github.AutomatedSecurityFixes
+		privateReportingEnabled *bool
+		expected                *SecurityAndAnalysis
+	}{
+		{
+			name: "all settings present",
+			input: &github.SecurityAndAnalysis{
+				AdvancedSecurity:             &github.AdvancedSecurity{Status: github.String("enabled")},
+				SecretScanning:               &github.SecretScanning{Status: github.String("enabled")},
+				SecretScanningPushProtection: &github.SecretScanningPushProtection{Status: github.String("disabled")},
+				DependabotSecurityUpdates:    &github.DependabotSecurityUpdates{Status: github.String("enabled")},
+			},
+			automatedSecurityFixes:  &github.AutomatedSecurityFixes{Enabled: github.Bool(true), Paused: github.Bool(false)},
+			privateReportingEnabled: github.Bool(true),
+			expected: &SecurityAndAnalysis{
+				AdvancedSecurity:              github.String("enabled"),
+				SecretScanning:                github.String("enabled"),
+				SecretScanningPushProtection:  github.String("disabled"),
+				DependabotSecurityUpdates:     github.String("enabled"),
+				AutomatedSecurityFixes:        github.Bool(true),
+				PrivateVulnerabilityReporting: github.Bool(true),
+			},
+		},
+		{
+			name:                   "only automated security fixes known",
+			automatedSecurityFixes: &github.AutomatedSecurityFixes{Enabled: github.Bool(false)},
+			expected: &SecurityAndAnalysis{
+				AutomatedSecurityFixes: github.Bool(false),
+			},
+		},
+		{
+			name:     "nothing known",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveSecurityAndAnalysis(tt.input, tt.automatedSecurityFixes, tt.privateReportingEnabled))
+		})
+	}
+}
+
+func TestResolveCustomProperties(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []*github.CustomPropertyValue
+		expected map[string]interface{}
+	}{
+		{
+			name: "string and multi select values",
+			input: []*github.CustomPropertyValue{
+				{PropertyName: "tier", Value: "1"},
+				{PropertyName: "owners", Value: []string{"platform", "security"}},
+				{PropertyName: "unset", Value: nil},
+			},
+			expected: map[string]interface{}{
+				"tier":   "1",
+				"owners": []string{"platform", "security"},
+			},
+		},
+		{
+			name:     "no values",
+			input:    nil,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveCustomProperties(tt.input))
+		})
+	}
+}
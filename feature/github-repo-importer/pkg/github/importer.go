@@ -0,0 +1,36 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// GraphQLClient is the part of githubv4.Client the importer relies on.
+type GraphQLClient interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// Importer reads repository settings through the GitHub clients it was constructed with.
+// It holds no global state, so it can be embedded by other Go programs and pointed at
+// fake servers in tests.
+type Importer struct {
+	v3client *github.Client
+	v4client GraphQLClient
+	dumpDir  string
+}
+
+func NewImporter(v3client *github.Client, v4client GraphQLClient) *Importer {
+	return &Importer{
+		v3client: v3client,
+		v4client: v4client,
+		dumpDir:  DefaultDumpDir,
+	}
+}
+
+// WithDumpDir sets where the raw API responses are dumped, by default ./dumps.
+// An empty dir disables dumping.
+func (i *Importer) WithDumpDir(dir string) *Importer {
+	i.dumpDir = dir
+	return i
+}
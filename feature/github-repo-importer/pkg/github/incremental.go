@@ -0,0 +1,107 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// ImportState is what an incremental bulk import remembers between runs.
+type ImportState struct {
+	// LastRun is when the last bulk import without failures started.
+	LastRun time.Time `json:"last_run"`
+}
+
+// LoadImportState reads the state file at path. It returns nil when there is none yet.
+func LoadImportState(path string) (*ImportState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import state %q: %w", path, err)
+	}
+
+	var state ImportState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode import state %q: %w", path, err)
+	}
+	return &state, nil
+}
+
+func (s ImportState) WriteJSONFile(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal import state: %w", err)
+	}
+
+	if err := file.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write import state %q: %w", path, err)
+	}
+	return nil
+}
+
+// ParseSince accepts either an RFC 3339 timestamp or a duration such as "90m",
+// which is counted back from now.
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	if since, err := time.Parse(time.RFC3339, value); err == nil {
+		return since, nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q, expected an RFC 3339 timestamp or a duration", value)
+	}
+	return now.Add(-age), nil
+}
+
+// isChangedSince reports whether the repository was updated or pushed to after since.
+// updated_at moves with most settings changes, pushed_at with new commits.
+func isChangedSince(repo *github.Repository, since time.Time) bool {
+	return repo.GetUpdatedAt().After(since) || repo.GetPushedAt().After(since)
+}
+
+// CollectAuditLogRepos returns the repositories the organization audit log recorded events
+// for since the given time. It catches settings changes, such as ruleset or team access
+// edits, that leave updated_at untouched. The audit log API needs GitHub Enterprise Cloud.
+func (i *Importer) CollectAuditLogRepos(org string, since time.Time) (map[string]bool, error) {
+	repos := make(map[string]bool)
+
+	opts := &github.GetAuditLogOptions{
+		Phrase:            github.String(fmt.Sprintf("created:>=%s", since.UTC().Format(time.RFC3339))),
+		ListCursorOptions: github.ListCursorOptions{PerPage: 100},
+	}
+
+	for {
+		entries, resp, err := i.v3client.Organizations.GetAuditLog(context.Background(), org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audit log: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		for _, entry := range entries {
+			if repo, ok := entry.AdditionalFields["repo"].(string); ok && repo != "" {
+				repos[repo] = true
+			}
+		}
+
+		if resp.After == "" {
+			break
+		}
+
+		opts.After = resp.After
+	}
+
+	return repos, nil
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Time
+		wantError bool
+	}{
+		{
+			name:     "timestamp",
+			value:    "2026-10-01T08:30:00Z",
+			expected: time.Date(2026, 10, 1, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "duration",
+			value:    "90m",
+			expected: now.Add(-90 * time.Minute),
+		},
+		{
+			name:      "invalid",
+			value:     "yesterday",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			since, err := ParseSince(tt.value, now)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(since), "expected %s, got %s", tt.expected, since)
+		})
+	}
+}
+
+func TestIsChangedSince(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	before := &github.Timestamp{Time: since.Add(-time.Hour)}
+	after := &github.Timestamp{Time: since.Add(time.Hour)}
+
+	tests := []struct {
+		name     string
+		repo     *github.Repository
+		expected bool
+	}{
+		{name: "no timestamps", repo: &github.Repository{}, expected: false},
+		{name: "untouched", repo: &github.Repository{UpdatedAt: before, PushedAt: before}, expected: false},
+		{name: "settings updated", repo: &github.Repository{UpdatedAt: after, PushedAt: before}, expected: true},
+		{name: "pushed to", repo: &github.Repository{UpdatedAt: before, PushedAt: after}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isChangedSince(tt.repo, since))
+		})
+	}
+}
+
+func TestImportReposSince(t *testing.T) {
+	importer, _, _ := newFakeOrgImporter(t)
+
+	tests := []struct {
+		name              string
+		useAuditLog       bool
+		expectedImported  []string
+		expectedUnchanged []string
+	}{
+		{
+			name:              "timestamps only",
+			expectedImported:  []string{"acme/broken"},
+			expectedUnchanged: []string{"acme/ok", "acme/ignored"},
+		},
+		{
+			name:              "audit log adds repositories with settings changes",
+			useAuditLog:       true,
+			expectedImported:  []string{"acme/ok", "acme/broken"},
+			expectedUnchanged: []string{"acme/ignored"},
+		},
+	}
+
+	pageSize := 100
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, report, err := importer.ImportRepos(Config{PageSize: &pageSize}, BulkImportOptions{
+				Owner:       "acme",
+				Since:       time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				UseAuditLog: tt.useAuditLog,
+			})
+			require.NoError(t, err)
+
+			var attempted []string
+			attempted = append(attempted, report.Succeeded...)
+			for _, failed := range report.Failed {
+				attempted = append(attempted, failed.Repository)
+			}
+			assert.ElementsMatch(t, tt.expectedImported, attempted)
+			assert.Equal(t, tt.expectedUnchanged, report.SkippedUnchanged)
+		})
+	}
+}
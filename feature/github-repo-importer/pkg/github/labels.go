@@ -0,0 +1,98 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type IssueLabel struct {
+	Name        string `yaml:"name"`
+	Color       string `yaml:"color"`
+	Description string `yaml:"description"`
+}
+
+// defaultIssueLabels is the label set GitHub creates for every new repository.
+var defaultIssueLabels = []IssueLabel{
+	{Name: "bug", Color: "d73a4a", Description: "Something isn't working"},
+	{Name: "documentation", Color: "0075ca", Description: "Improvements or additions to documentation"},
+	{Name: "duplicate", Color: "cfd3d7", Description: "This issue or pull request already exists"},
+	{Name: "enhancement", Color: "a2eeef", Description: "New feature or request"},
+	{Name: "good first issue", Color: "7057ff", Description: "Good for newcomers"},
+	{Name: "help wanted", Color: "008672", Description: "Extra attention is needed"},
+	{Name: "invalid", Color: "e4e669", Description: "This doesn't seem right"},
+	{Name: "question", Color: "d876e3", Description: "Further information is requested"},
+	{Name: "wontfix", Color: "ffffff", Description: "This will not be worked on"},
+}
+
+func CollectIssueLabels(client *github.Client, owner, repo string, excludeDefaults bool, dumpManager *file.DumpManager) ([]IssueLabel, error) {
+	var labels []IssueLabel
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		ghLabels, resp, err := client.Issues.ListLabels(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list labels: %w", err)
+		}
+
+		filename := fmt.Sprintf("labels-page_%d.json", opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, ghLabels); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		labels = append(labels, resolveIssueLabels(ghLabels, excludeDefaults)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return labels, nil
+}
+
+func resolveIssueLabels(ghLabels []*github.Label, excludeDefaults bool) []IssueLabel {
+	var labels []IssueLabel
+	for _, ghLabel := range ghLabels {
+		if ghLabel == nil {
+			continue
+		}
+
+		label := IssueLabel{
+			Name:        ghLabel.GetName(),
+			Color:       ghLabel.GetColor(),
+			Description: ghLabel.GetDescription(),
+		}
+
+		if excludeDefaults && isDefaultIssueLabel(label) {
+			continue
+		}
+
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// isDefaultIssueLabel reports whether the label is one of GitHub's default labels
+// left untouched. A default label with a changed colour or description is a customisation.
+func isDefaultIssueLabel(label IssueLabel) bool {
+	for _, d := range defaultIssueLabels {
+		if strings.EqualFold(d.Name, label.Name) &&
+			strings.EqualFold(d.Color, label.Color) &&
+			d.Description == label.Description {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package github
+
+import "time"
+
+// ImportOptions tunes what ImportRepo writes into the generated Repository.
+type ImportOptions struct {
+	// ExcludeDefaultLabels leaves GitHub's unmodified default issue labels out of issue_labels.
+	ExcludeDefaultLabels bool
+}
+
+// BulkImportOptions hooks into ImportRepos.
+type BulkImportOptions struct {
+	// Owner restricts the import to one organization, see Config.ResolveOrganizations.
+	Owner string
+	// Skip leaves out repositories that do not need importing, for example ones a
+	// resumed run already imported.
+	Skip func(repoName string) bool
+	// OnImported is called as soon as a repository is imported, concurrently from the
+	// workers importing the repositories. An error marks the repository as failed.
+	OnImported func(repoName string, repository *Repository) error
+	// Since, when set, only imports listed repositories updated or pushed to after it.
+	// Explicitly selected repositories are always imported.
+	Since time.Time
+	// UseAuditLog additionally imports repositories the organization audit log recorded
+	// events for since Since, which catches settings changes that leave updated_at alone.
+	UseAuditLog bool
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// ImportReport is the machine readable outcome of a bulk import. Repositories are
+// listed by their full name.
+type ImportReport struct {
+	Succeeded       []string       `json:"succeeded"`
+	Failed          []FailedImport `json:"failed"`
+	SkippedIgnored  []string       `json:"skipped_ignored"`
+	SkippedArchived []string       `json:"skipped_archived"`
+	// SkippedFiltered lists repositories the configured filters left out.
+	SkippedFiltered []FilteredRepo `json:"skipped_filtered"`
+	// SkippedCompleted lists repositories a resumed run had already imported.
+	SkippedCompleted []string `json:"skipped_completed"`
+	// SkippedUnchanged lists repositories an incremental run found unchanged.
+	SkippedUnchanged []string        `json:"skipped_unchanged"`
+	Warnings         []ImportWarning `json:"warnings"`
+}
+
+type FailedImport struct {
+	Repository string `json:"repository"`
+	Reason     string `json:"reason"`
+}
+
+type FilteredRepo struct {
+	Repository string `json:"repository"`
+	Reason     string `json:"reason"`
+}
+
+// ImportWarning is a setting that could not be imported without failing the repository,
+// for example rulesets the token is not allowed to read.
+type ImportWarning struct {
+	Repository string `json:"repository"`
+	Message    string `json:"message"`
+}
+
+func NewImportReport() *ImportReport {
+	return &ImportReport{
+		Succeeded:        []string{},
+		Failed:           []FailedImport{},
+		SkippedIgnored:   []string{},
+		SkippedArchived:  []string{},
+		SkippedFiltered:  []FilteredRepo{},
+		SkippedCompleted: []string{},
+		SkippedUnchanged: []string{},
+		Warnings:         []ImportWarning{},
+	}
+}
+
+// Merge appends the outcome of another run, such as the import of another organization.
+func (r *ImportReport) Merge(other *ImportReport) {
+	r.Succeeded = append(r.Succeeded, other.Succeeded...)
+	r.Failed = append(r.Failed, other.Failed...)
+	r.SkippedIgnored = append(r.SkippedIgnored, other.SkippedIgnored...)
+	r.SkippedArchived = append(r.SkippedArchived, other.SkippedArchived...)
+	r.SkippedFiltered = append(r.SkippedFiltered, other.SkippedFiltered...)
+	r.SkippedCompleted = append(r.SkippedCompleted, other.SkippedCompleted...)
+	r.SkippedUnchanged = append(r.SkippedUnchanged, other.SkippedUnchanged...)
+	r.Warnings = append(r.Warnings, other.Warnings...)
+}
+
+// ExceedsFailureThreshold reports whether more than maxFailures repositories failed.
+func (r *ImportReport) ExceedsFailureThreshold(maxFailures int) bool {
+	return len(r.Failed) > maxFailures
+}
+
+func (r *ImportReport) Summary() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "imported %d repositories, %d failed, %d ignored, %d archived, %d filtered, %d already imported, %d unchanged, %d warnings\n",
+		len(r.Succeeded), len(r.Failed), len(r.SkippedIgnored), len(r.SkippedArchived), len(r.SkippedFiltered), len(r.SkippedCompleted), len(r.SkippedUnchanged), len(r.Warnings))
+	for _, failed := range r.Failed {
+		fmt.Fprintf(&sb, "  failed %s: %s\n", failed.Repository, failed.Reason)
+	}
+	return sb.String()
+}
+
+func (r *ImportReport) WriteJSONFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal import report: %w", err)
+	}
+
+	if err := file.WriteFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write import report %q: %w", path, err)
+	}
+	return nil
+}
+
+// importWarnings collects the non-fatal problems of a single repository import.
+// The fetches of a repository run concurrently, so it is safe for concurrent use.
+type importWarnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// Warnf prints the warning, as the importer always did, and records it for the report.
+func (w *importWarnings) Warnf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	fmt.Println(message)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, message)
+}
+
+// sorted returns the warnings in a stable order, independent of which fetch finished first.
+func (w *importWarnings) sorted() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	messages := slices.Clone(w.messages)
+	slices.Sort(messages)
+	return messages
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/shurcooL/githubv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeOrgServer serves an organization with an importable, a broken, an ignored and an
+// archived repository. Settings endpoints answer with empty lists and objects.
+func newFakeOrgServer() *httptest.Server {
+	listEndpoints := []string{"collaborators", "teams", "hooks", "keys", "labels", "autolinks", "values"}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/graphql":
+			_, _ = w.Write([]byte(`{"data":{"repository":{"branchProtectionRules":{"nodes":[],"pageInfo":{"hasNextPage":false}}}}}`))
+		case "/api/v3/orgs/acme/repos":
+			_, _ = w.Write([]byte(`[
+				{"full_name":"acme/ok"},
+				{"full_name":"acme/broken","pushed_at":"2030-01-01T00:00:00Z"},
+				{"full_name":"acme/ignored"},
+				{"full_name":"acme/old","archived":true}]`))
+		case "/api/v3/orgs/acme/properties/values":
+			_, _ = w.Write([]byte(`[
+				{"repository_full_name":"acme/api","properties":[{"property_name":"tier","value":"low"}]},
+				{"repository_full_name":"acme/web","properties":[{"property_name":"team","value":["platform","security"]}]}]`))
+		case "/api/v3/orgs/acme/audit-log":
+			_, _ = w.Write([]byte(`[{"action":"repository_ruleset.update","repo":"acme/ok"}]`))
+		case "/api/v3/repos/acme/ok":
+			_, _ = w.Write([]byte(`{"name":"ok","owner":{"login":"acme"}}`))
+		case "/api/v3/repos/acme/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/api/v3/repos/acme/ok/collaborators":
+			_, _ = w.Write([]byte(`[{"login":"octocat","role_name":"admin"}]`))
+		case "/api/v3/repos/acme/ok/hooks":
+			_, _ = w.Write([]byte(`[{"active":true,"events":["push"],"config":{"url":"https://ci.example.com","secret":"s3cr3t"}}]`))
+		case "/api/v3/repos/acme/ok/environments":
+			_, _ = w.Write([]byte(`{"total_count":1,"environments":[{"name":"prod","protection_rules":[{"type":"wait_timer","wait_timer":5}]}]}`))
+		case "/api/v3/repos/acme/ok/environments/prod/variables":
+			_, _ = w.Write([]byte(`{"total_count":1,"variables":[{"name":"REGION","value":"eu"}]}`))
+		case "/api/v3/repos/acme/ok/rulesets":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"Resource not accessible by integration"}`))
+		case "/api/v3/repos/acme/ok/pages":
+			w.WriteHeader(http.StatusNotFound)
+		case "/api/v3/repos/acme/ok/vulnerability-alerts":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			if slices.Contains(listEndpoints, path.Base(r.URL.Path)) {
+				_, _ = w.Write([]byte(`[]`))
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+}
+
+// newFakeOrgImporter returns an importer for newFakeOrgServer that dumps to a temporary
+// directory, along with the server and that directory.
+func newFakeOrgImporter(t *testing.T) (*Importer, *httptest.Server, string) {
+	t.Helper()
+
+	server := newFakeOrgServer()
+	t.Cleanup(server.Close)
+
+	v3client, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
+	require.NoError(t, err)
+
+	dumpDir := t.TempDir()
+	importer := NewImporter(v3client, githubv4.NewEnterpriseClient(server.URL+"/api/graphql", nil)).WithDumpDir(dumpDir)
+	return importer, server, dumpDir
+}
+
+func TestImportReposContinuesPastFailures(t *testing.T) {
+	importer, _, _ := newFakeOrgImporter(t)
+
+	pageSize := 100
+	repos, report, err := importer.ImportRepos(Config{PageSize: &pageSize, IgnoredRepos: []string{"acme/ignored"}}, BulkImportOptions{Owner: "acme"})
+	require.NoError(t, err)
+
+	require.Len(t, repos, 1)
+	assert.Equal(t, "ok", repos[0].Name)
+
+	assert.Equal(t, []string{"acme/ok"}, report.Succeeded)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, "acme/broken", report.Failed[0].Repository)
+	assert.Contains(t, report.Failed[0].Reason, "failed to fetch repo")
+	assert.Equal(t, []string{"acme/ignored"}, report.SkippedIgnored)
+	assert.Equal(t, []string{"acme/old"}, report.SkippedArchived)
+
+	var rulesetWarnings []ImportWarning
+	for _, warning := range report.Warnings {
+		assert.Equal(t, "acme/ok", warning.Repository)
+		if strings.HasPrefix(warning.Message, "skipping rulesets due to insufficient permissions") {
+			rulesetWarnings = append(rulesetWarnings, warning)
+		}
+	}
+	assert.Len(t, rulesetWarnings, 1)
+
+	assert.False(t, report.ExceedsFailureThreshold(1))
+	assert.True(t, report.ExceedsFailureThreshold(0))
+}
+
+func TestImportReposRejectsConcurrencyBelowOne(t *testing.T) {
+	importer, _, _ := newFakeOrgImporter(t)
+
+	concurrency := 0
+	repos, report, err := importer.ImportRepos(Config{Concurrency: &concurrency, SelectedRepos: []string{"acme/ok"}}, BulkImportOptions{Owner: "acme"})
+	assert.EqualError(t, err, "concurrency must be at least 1")
+	assert.Nil(t, repos)
+	assert.Nil(t, report)
+}
+
+func TestImportReposHooks(t *testing.T) {
+	importer, _, _ := newFakeOrgImporter(t)
+
+	var imported []string
+	repos, report, err := importer.ImportRepos(Config{SelectedRepos: []string{"acme/ok", "acme/old"}}, BulkImportOptions{
+		Owner: "acme",
+		Skip:  func(repoName string) bool { return repoName == "acme/old" },
+		OnImported: func(repoName string, repository *Repository) error {
+			imported = append(imported, repoName)
+			return assert.AnError
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, repos)
+	assert.Equal(t, []string{"acme/ok"}, imported)
+	assert.Equal(t, []string{"acme/old"}, report.SkippedCompleted)
+	assert.Equal(t, []FailedImport{{Repository: "acme/ok", Reason: assert.AnError.Error()}}, report.Failed)
+}
@@ -0,0 +1,292 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryPolicy controls how requests that hit a rate limit or a transient server error are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a request is retried before its last response is returned.
+	MaxRetries int
+	// MaxWait caps a single pause. A rate limit that resets later than that fails the request instead.
+	MaxWait time.Duration
+	// MinRemaining pauses requests until the quota resets once no more than this many
+	// requests are left, so concurrent imports never run the hourly quota dry.
+	MinRemaining int
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries:   5,
+	MaxWait:      time.Hour,
+	MinRemaining: 100,
+}
+
+const (
+	retryBaseBackoff = time.Second
+	// secondaryRateLimitBackoff is what GitHub asks for when a secondary rate limit
+	// response carries no Retry-After header.
+	secondaryRateLimitBackoff = time.Minute
+)
+
+// rateLimitBudget is the last known quota of a rate limit resource (core, graphql, search, ...).
+type rateLimitBudget struct {
+	remaining int
+	reset     time.Time
+}
+
+// retryTransport retries rate limited and transiently failed requests below the GitHub clients,
+// so every REST and GraphQL call of the importer gets the same treatment.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	budgets map[string]rateLimitBudget
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:    base,
+		policy:  policy,
+		now:     time.Now,
+		sleep:   sleepContext,
+		budgets: make(map[string]rateLimitBudget),
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.waitForBudget(req); err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err != nil {
+			return nil, err
+		}
+		t.recordBudget(resp)
+
+		wait, reason, err := t.retryAfter(req, resp, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if reason == "" || attempt >= t.policy.MaxRetries || wait > t.policy.MaxWait || !canRetry(req) {
+			return resp, nil
+		}
+
+		fmt.Printf("%s on %s %s, retrying in %s (attempt %d of %d)\n", reason, req.Method, req.URL.Path, wait, attempt+1, t.policy.MaxRetries)
+		drainBody(resp)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryAfter returns how long to wait before retrying resp, and why. An empty reason means
+// the response is final.
+func (t *retryTransport) retryAfter(req *http.Request, resp *http.Response, attempt int) (time.Duration, string, error) {
+	backoff := min(retryBaseBackoff<<attempt, t.policy.MaxWait)
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return backoff, fmt.Sprintf("server error %d", resp.StatusCode), nil
+
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if wait, ok := t.retryAfterHeader(resp); ok {
+			return wait, "secondary rate limit", nil
+		}
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return t.untilReset(resp, backoff), "primary rate limit", nil
+		}
+
+		body, err := peekBody(resp)
+		if err != nil {
+			return 0, "", err
+		}
+		if bytes.Contains(bytes.ToLower(body), []byte("secondary rate limit")) {
+			return max(backoff, secondaryRateLimitBackoff), "secondary rate limit", nil
+		}
+
+	case http.StatusOK:
+		if !isGraphQLRequest(req) {
+			return 0, "", nil
+		}
+
+		body, err := peekBody(resp)
+		if err != nil {
+			return 0, "", err
+		}
+		if isGraphQLRateLimited(body) {
+			return t.untilReset(resp, backoff), "GraphQL rate limit", nil
+		}
+	}
+
+	return 0, "", nil
+}
+
+func (t *retryTransport) retryAfterHeader(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(t.now()), 0), true
+	}
+	return 0, false
+}
+
+// untilReset returns the time left until the quota in the X-RateLimit-Reset header resets,
+// or fallback when the header is missing.
+func (t *retryTransport) untilReset(resp *http.Response, fallback time.Duration) time.Duration {
+	reset, ok := parseRateLimitReset(resp.Header)
+	if !ok {
+		return fallback
+	}
+	// A second of slack absorbs clock skew between us and GitHub.
+	return max(reset.Sub(t.now()), 0) + time.Second
+}
+
+// waitForBudget pauses the request while the quota of its resource is nearly used up.
+func (t *retryTransport) waitForBudget(req *http.Request) error {
+	resource := requestResource(req)
+
+	t.mu.Lock()
+	budget, ok := t.budgets[resource]
+	t.mu.Unlock()
+
+	if !ok || budget.remaining > t.policy.MinRemaining {
+		return nil
+	}
+
+	wait := budget.reset.Sub(t.now())
+	if wait <= 0 || wait > t.policy.MaxWait {
+		return nil
+	}
+
+	fmt.Printf("%s rate limit nearly exhausted (%d requests left), pausing until %s\n", resource, budget.remaining, budget.reset.Format(time.RFC3339))
+	return t.sleep(req.Context(), wait+time.Second)
+}
+
+func (t *retryTransport) recordBudget(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, ok := parseRateLimitReset(resp.Header)
+	if !ok {
+		return
+	}
+
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = requestResource(resp.Request)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.budgets[resource] = rateLimitBudget{remaining: remaining, reset: reset}
+}
+
+func parseRateLimitReset(header http.Header) (time.Time, bool) {
+	epoch, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(epoch, 0), true
+}
+
+// requestResource guesses the rate limit resource a request counts against before its
+// response names it.
+func requestResource(req *http.Request) string {
+	switch {
+	case req == nil:
+		return "core"
+	case isGraphQLRequest(req):
+		return "graphql"
+	case strings.Contains(req.URL.Path, "/search/"):
+		return "search"
+	default:
+		return "core"
+	}
+}
+
+func isGraphQLRequest(req *http.Request) bool {
+	return strings.HasSuffix(strings.TrimSuffix(req.URL.Path, "/"), "/graphql")
+}
+
+func isGraphQLRateLimited(body []byte) bool {
+	var response struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return false
+	}
+	for _, e := range response.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
+
+// canRetry reports whether the request body, if any, can be sent again.
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// peekBody reads the response body and puts it back, so the caller still sees it.
+func peekBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func drainBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
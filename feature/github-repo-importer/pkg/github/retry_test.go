@@ -0,0 +1,202 @@
+package github
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type fakeResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+func TestRetryTransport(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	resetIn := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+	ok := fakeResponse{status: http.StatusOK, body: `{"ok":true}`}
+
+	tests := []struct {
+		name           string
+		path           string
+		policy         RetryPolicy
+		responses      []fakeResponse
+		expectedStatus int
+		expectedCalls  int
+		expectedSleeps []time.Duration
+	}{
+		{
+			name:           "bad gateway is retried with backoff",
+			path:           "/repos/o/r",
+			responses:      []fakeResponse{{status: http.StatusBadGateway}, {status: http.StatusBadGateway}, ok},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+			expectedSleeps: []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name: "secondary rate limit honours Retry-After",
+			path: "/repos/o/r",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"Retry-After": "30"}, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{30 * time.Second},
+		},
+		{
+			name: "secondary rate limit without Retry-After waits a minute",
+			path: "/repos/o/r",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit."}`},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{time.Minute},
+		},
+		{
+			name: "primary rate limit waits until reset",
+			path: "/repos/o/r",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": resetIn(10 * time.Minute)}},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{10*time.Minute + time.Second},
+		},
+		{
+			name: "primary rate limit resetting after max wait is returned",
+			path: "/repos/o/r",
+			responses: []fakeResponse{
+				{status: http.StatusForbidden, headers: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": resetIn(2 * time.Hour)}},
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedCalls:  1,
+		},
+		{
+			name:           "permission denied is not retried",
+			path:           "/repos/o/r/rulesets",
+			responses:      []fakeResponse{{status: http.StatusForbidden, body: `{"message":"Resource not accessible by integration"}`}},
+			expectedStatus: http.StatusForbidden,
+			expectedCalls:  1,
+		},
+		{
+			name: "GraphQL RATE_LIMITED error is retried",
+			path: "/graphql",
+			responses: []fakeResponse{
+				{status: http.StatusOK, headers: map[string]string{"X-RateLimit-Reset": resetIn(time.Minute)}, body: `{"errors":[{"type":"RATE_LIMITED","message":"API rate limit exceeded"}]}`},
+				ok,
+			},
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{time.Minute + time.Second},
+		},
+		{
+			name:           "last response is returned once retries are used up",
+			path:           "/repos/o/r",
+			policy:         RetryPolicy{MaxRetries: 1, MaxWait: time.Hour},
+			responses:      []fakeResponse{{status: http.StatusBadGateway}, {status: http.StatusBadGateway}, ok},
+			expectedStatus: http.StatusBadGateway,
+			expectedCalls:  2,
+			expectedSleeps: []time.Duration{time.Second},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, `{"query":"{}"}`, string(body), "request body must be replayed on retries")
+
+				response := tt.responses[min(int(calls.Add(1))-1, len(tt.responses)-1)]
+				for k, v := range response.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(response.status)
+				_, _ = w.Write([]byte(response.body))
+			}))
+			defer server.Close()
+
+			policy := tt.policy
+			if policy == (RetryPolicy{}) {
+				policy = DefaultRetryPolicy
+			}
+			transport := newRetryTransport(http.DefaultTransport, policy)
+			transport.now = func() time.Time { return now }
+			var sleeps []time.Duration
+			transport.sleep = func(_ context.Context, d time.Duration) error {
+				sleeps = append(sleeps, d)
+				return nil
+			}
+
+			req, err := http.NewRequest(http.MethodPost, server.URL+tt.path, strings.NewReader(`{"query":"{}"}`))
+			require.NoError(t, err)
+
+			resp, err := (&http.Client{Transport: transport}).Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			assert.Equal(t, int32(tt.expectedCalls), calls.Load())
+			assert.Equal(t, tt.expectedSleeps, sleeps)
+		})
+	}
+}
+
+func TestRetryTransportPausesBeforeQuotaRunsOut(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reset := now.Add(20 * time.Minute)
+
+	var remaining atomic.Int32
+	remaining.Store(101)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Resource", "core")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(remaining.Add(-1))))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}))
+	defer server.Close()
+
+	transport := newRetryTransport(http.DefaultTransport, DefaultRetryPolicy)
+	transport.now = func() time.Time { return now }
+	var sleeps []time.Duration
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	client := &http.Client{Transport: transport}
+
+	for n := 0; n < 2; n++ {
+		resp, err := client.Get(server.URL + "/repos/o/r")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, []time.Duration{20*time.Minute + time.Second}, sleeps, "second request must wait for the reset once 100 requests are left")
+}
+
+func TestRateLimitConfigRetryPolicy(t *testing.T) {
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte("rate_limit:\n  max_wait: 15m\n  min_remaining: 0\n"), &cfg))
+	require.NoError(t, cfg.Validate())
+
+	assert.Equal(t, RetryPolicy{
+		MaxRetries:   DefaultRetryPolicy.MaxRetries,
+		MaxWait:      15 * time.Minute,
+		MinRemaining: 0,
+	}, cfg.RateLimit.RetryPolicy())
+}
@@ -0,0 +1,159 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+// ActionsSecret records that a secret exists. GitHub never returns secret values,
+// and the importer never writes them.
+type ActionsSecret struct {
+	Name      string `yaml:"name"`
+	UpdatedAt string `yaml:"updated_at,omitempty"`
+}
+
+type ActionsVariable struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type listSecretsFunc func(opts *github.ListOptions) (*github.Secrets, *github.Response, error)
+
+type listVariablesFunc func(opts *github.ListOptions) (*github.ActionsVariables, *github.Response, error)
+
+func CollectActionsSecrets(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]ActionsSecret, error) {
+	return collectSecrets(func(opts *github.ListOptions) (*github.Secrets, *github.Response, error) {
+		return client.Actions.ListRepoSecrets(context.Background(), owner, repo, opts)
+	}, "actions_secrets", dumpManager)
+}
+
+func CollectActionsVariables(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]ActionsVariable, error) {
+	return collectVariables(func(opts *github.ListOptions) (*github.ActionsVariables, *github.Response, error) {
+		return client.Actions.ListRepoVariables(context.Background(), owner, repo, opts)
+	}, "actions_variables", dumpManager)
+}
+
+func CollectEnvironmentSecrets(client *github.Client, repoID int64, env string, dumpManager *file.DumpManager) ([]ActionsSecret, error) {
+	return collectSecrets(func(opts *github.ListOptions) (*github.Secrets, *github.Response, error) {
+		return client.Actions.ListEnvSecrets(context.Background(), int(repoID), env, opts)
+	}, fmt.Sprintf("environment-%s-secrets", environmentDumpName(env)), dumpManager)
+}
+
+func CollectEnvironmentVariables(client *github.Client, owner, repo, env string, dumpManager *file.DumpManager) ([]ActionsVariable, error) {
+	return collectVariables(func(opts *github.ListOptions) (*github.ActionsVariables, *github.Response, error) {
+		return client.Actions.ListEnvVariables(context.Background(), owner, repo, env, opts)
+	}, fmt.Sprintf("environment-%s-variables", environmentDumpName(env)), dumpManager)
+}
+
+func collectSecrets(list listSecretsFunc, dumpPrefix string, dumpManager *file.DumpManager) ([]ActionsSecret, error) {
+	var secrets []ActionsSecret
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		ghSecrets, resp, err := list(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+
+		filename := fmt.Sprintf("%s-page_%d.json", dumpPrefix, opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, ghSecrets); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		secrets = append(secrets, resolveSecrets(ghSecrets)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return secrets, nil
+}
+
+func collectVariables(list listVariablesFunc, dumpPrefix string, dumpManager *file.DumpManager) ([]ActionsVariable, error) {
+	var variables []ActionsVariable
+
+	opts := &github.ListOptions{PerPage: 30}
+
+	for {
+		ghVariables, resp, err := list(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variables: %w", err)
+		}
+
+		filename := fmt.Sprintf("%s-page_%d.json", dumpPrefix, opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, ghVariables); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		variables = append(variables, resolveVariables(ghVariables)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return variables, nil
+}
+
+func resolveSecrets(ghSecrets *github.Secrets) []ActionsSecret {
+	if ghSecrets == nil {
+		return nil
+	}
+
+	var secrets []ActionsSecret
+	for _, secret := range ghSecrets.Secrets {
+		if secret == nil {
+			continue
+		}
+
+		var updatedAt string
+		if !secret.UpdatedAt.IsZero() {
+			updatedAt = secret.UpdatedAt.UTC().Format(time.RFC3339)
+		}
+
+		secrets = append(secrets, ActionsSecret{
+			Name:      secret.Name,
+			UpdatedAt: updatedAt,
+		})
+	}
+	return secrets
+}
+
+func resolveVariables(ghVariables *github.ActionsVariables) []ActionsVariable {
+	if ghVariables == nil {
+		return nil
+	}
+
+	var variables []ActionsVariable
+	for _, variable := range ghVariables.Variables {
+		if variable == nil {
+			continue
+		}
+
+		variables = append(variables, ActionsVariable{
+			Name:  variable.Name,
+			Value: variable.Value,
+		})
+	}
+	return variables
+}
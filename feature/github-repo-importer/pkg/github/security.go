@@ -0,0 +1,80 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+type SecurityAndAnalysis struct {
+	AdvancedSecurity              *string `yaml:"advanced_security,omitempty"`
+	SecretScanning                *string `yaml:"secret_scanning,omitempty"`
+	SecretScanningPushProtection  *string `yaml:"secret_scanning_push_protection,omitempty"`
+	SecretScanningValidityChecks  *string `yaml:"secret_scanning_validity_checks,omitempty"`
+	DependabotSecurityUpdates     *string `yaml:"dependabot_security_updates,omitempty"`
+	AutomatedSecurityFixes        *bool   `yaml:"automated_security_fixes,omitempty"`
+	PrivateVulnerabilityReporting *bool   `yaml:"private_vulnerability_reporting,omitempty"`
+}
+
+// CollectSecurityAndAnalysis combines the security_and_analysis section of an already
+// fetched repository with the endpoints that are not part of the repository payload.
+// A failing endpoint leaves its field unset; the partial result is returned with the error.
+func CollectSecurityAndAnalysis(client *github.Client, repo *github.Repository, dumpManager *file.DumpManager) (*SecurityAndAnalysis, error) {
+	owner, name := repo.GetOwner().GetLogin(), repo.GetName()
+	var errs []error
+
+	automatedSecurityFixes, _, err := client.Repositories.GetAutomatedSecurityFixes(context.Background(), owner, name)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get automated security fixes: %w", err))
+	} else if err := dumpManager.WriteJSONFile("automated_security_fixes.json", automatedSecurityFixes); err != nil {
+		fmt.Printf("failed to write automated_security_fixes.json: %v\n", err)
+	}
+
+	var privateReportingEnabled *bool
+	enabled, _, err := client.Repositories.IsPrivateReportingEnabled(context.Background(), owner, name)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to get private vulnerability reporting: %w", err))
+	} else {
+		privateReportingEnabled = &enabled
+		if err := dumpManager.WriteJSONFile("private_vulnerability_reporting.json", map[string]bool{"enabled": enabled}); err != nil {
+			fmt.Printf("failed to write private_vulnerability_reporting.json: %v\n", err)
+		}
+	}
+
+	return resolveSecurityAndAnalysis(repo.GetSecurityAndAnalysis(), automatedSecurityFixes, privateReportingEnabled), errors.Join(errs...)
+}
+
+func resolveSecurityAndAnalysis(sa *github.SecurityAndAnalysis, automatedSecurityFixes *github.AutomatedSecurityFixes, privateReportingEnabled *bool) *SecurityAndAnalysis {
+	if sa == nil && automatedSecurityFixes == nil && privateReportingEnabled == nil {
+		return nil
+	}
+
+	result := &SecurityAndAnalysis{
+		PrivateVulnerabilityReporting: privateReportingEnabled,
+	}
+
+	if sa != nil {
+		result.AdvancedSecurity = securityStatus(sa.GetAdvancedSecurity())
+		result.SecretScanning = securityStatus(sa.GetSecretScanning())
+		result.SecretScanningPushProtection = securityStatus(sa.GetSecretScanningPushProtection())
+		result.SecretScanningValidityChecks = securityStatus(sa.GetSecretScanningValidityChecks())
+		result.DependabotSecurityUpdates = securityStatus(sa.GetDependabotSecurityUpdates())
+	}
+
+	if automatedSecurityFixes != nil {
+		result.AutomatedSecurityFixes = automatedSecurityFixes.Enabled
+	}
+
+	return result
+}
+
+func securityStatus(setting interface{ GetStatus() string }) *string {
+	if status := setting.GetStatus(); status != "" {
+		return &status
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+)
+
+// RepoFilters narrows down the repositories a bulk import lists from the organization.
+// A repository has to pass every filter that is set. Explicitly selected repositories
+// are imported as listed.
+type RepoFilters struct {
+	// Visibility lists the visibilities to import: public, private and/or internal.
+	Visibility []string `yaml:"visibility,omitempty"`
+	// IncludeForks imports forks too. Defaults to true.
+	IncludeForks *bool `yaml:"include_forks,omitempty"`
+	// IncludeArchived imports archived repositories too. Defaults to false.
+	IncludeArchived *bool `yaml:"include_archived,omitempty"`
+	// NamePatterns are shell globs, such as "terraform-*", matched against the repository name.
+	NamePatterns []string `yaml:"name_patterns,omitempty"`
+	// NameRegex is a regular expression matched against the repository name.
+	NameRegex *string `yaml:"name_regex,omitempty"`
+	// Topics imports repositories having at least one of them.
+	Topics []string `yaml:"topics,omitempty"`
+	// CustomProperties imports repositories whose custom properties have all of these values.
+	CustomProperties map[string]string `yaml:"custom_properties,omitempty"`
+	// PushedWithin imports repositories pushed to within this duration, e.g. 2160h.
+	PushedWithin *time.Duration `yaml:"pushed_within,omitempty"`
+}
+
+func (f *RepoFilters) Validate() error {
+	for _, visibility := range f.Visibility {
+		if !slices.Contains([]string{VisibilityPublic, VisibilityPrivate, VisibilityInternal}, visibility) {
+			return fmt.Errorf("invalid visibility %q, must be one of public, private or internal", visibility)
+		}
+	}
+	for _, pattern := range f.NamePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+	}
+	if f.NameRegex != nil {
+		if _, err := regexp.Compile(*f.NameRegex); err != nil {
+			return fmt.Errorf("invalid name regex %q: %w", *f.NameRegex, err)
+		}
+	}
+	if f.PushedWithin != nil && *f.PushedWithin <= 0 {
+		return errors.New("pushed_within must be positive")
+	}
+	return nil
+}
+
+// repoSelector applies the filters of a configuration to listed repositories, before any
+// repository specific API call is made.
+type repoSelector struct {
+	filters    RepoFilters
+	nameRegex  *regexp.Regexp
+	properties map[string]map[string]interface{}
+	now        time.Time
+}
+
+// newRepoSelector combines is_public with the filters of an organization. Custom property
+// values are fetched for the whole organization at once, and only when a filter needs them.
+func (i *Importer) newRepoSelector(org OrganizationConfig) (*repoSelector, error) {
+	selector := &repoSelector{now: time.Now()}
+	if org.Filters != nil {
+		selector.filters = *org.Filters
+	}
+
+	// Config.Validate rejects is_public next to filters.visibility.
+	if org.IsPublic != nil {
+		if *org.IsPublic {
+			selector.filters.Visibility = []string{VisibilityPublic}
+		} else {
+			selector.filters.Visibility = []string{VisibilityPrivate, VisibilityInternal}
+		}
+	}
+
+	if selector.filters.NameRegex != nil {
+		nameRegex, err := regexp.Compile(*selector.filters.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name regex %q: %w", *selector.filters.NameRegex, err)
+		}
+		selector.nameRegex = nameRegex
+	}
+
+	if len(selector.filters.CustomProperties) > 0 {
+		properties, err := i.collectOrgCustomPropertyValues(org.Name)
+		if err != nil {
+			return nil, err
+		}
+		selector.properties = properties
+	}
+
+	return selector, nil
+}
+
+// skipReason returns why a repository is filtered out, or "" when it is selected.
+func (s *repoSelector) skipReason(repo *github.Repository) string {
+	if repo.GetArchived() && (s.filters.IncludeArchived == nil || !*s.filters.IncludeArchived) {
+		return "archived"
+	}
+
+	if repo.GetFork() && s.filters.IncludeForks != nil && !*s.filters.IncludeForks {
+		return "fork"
+	}
+
+	if len(s.filters.Visibility) > 0 && !slices.Contains(s.filters.Visibility, repoVisibility(repo)) {
+		return fmt.Sprintf("visibility %s", repoVisibility(repo))
+	}
+
+	if len(s.filters.NamePatterns) > 0 && !slices.ContainsFunc(s.filters.NamePatterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, repo.GetName())
+		return matched
+	}) {
+		return "name does not match name_patterns"
+	}
+
+	if s.nameRegex != nil && !s.nameRegex.MatchString(repo.GetName()) {
+		return "name does not match name_regex"
+	}
+
+	if len(s.filters.Topics) > 0 && !slices.ContainsFunc(repo.Topics, func(topic string) bool {
+		return slices.Contains(s.filters.Topics, topic)
+	}) {
+		return "no matching topic"
+	}
+
+	for name, value := range s.filters.CustomProperties {
+		if !customPropertyHasValue(s.properties[repo.GetFullName()][name], value) {
+			return fmt.Sprintf("custom property %s is not %q", name, value)
+		}
+	}
+
+	if s.filters.PushedWithin != nil && repo.GetPushedAt().Before(s.now.Add(-*s.filters.PushedWithin)) {
+		return fmt.Sprintf("not pushed to within %s", *s.filters.PushedWithin)
+	}
+
+	return ""
+}
+
+// repoVisibility falls back to the private flag for servers that do not return visibility.
+func repoVisibility(repo *github.Repository) string {
+	if visibility := repo.GetVisibility(); visibility != "" {
+		return visibility
+	}
+	return resolveVisibility(repo.GetPrivate())
+}
+
+// customPropertyHasValue matches single values as well as multi select properties.
+func customPropertyHasValue(actual interface{}, expected string) bool {
+	switch v := actual.(type) {
+	case string:
+		return v == expected
+	case []string:
+		return slices.Contains(v, expected)
+	case []interface{}:
+		return slices.Contains(v, interface{}(expected))
+	default:
+		return false
+	}
+}
+
+// collectOrgCustomPropertyValues returns the custom property values of every repository
+// of the organization, keyed by repository full name and property name.
+func (i *Importer) collectOrgCustomPropertyValues(org string) (map[string]map[string]interface{}, error) {
+	properties := make(map[string]map[string]interface{})
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		values, resp, err := i.v3client.Organizations.ListCustomPropertyValues(context.Background(), org, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list custom property values: %w", err)
+		}
+
+		for _, repo := range values {
+			properties[repo.RepositoryFullName] = resolveCustomProperties(repo.Properties)
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return properties, nil
+}
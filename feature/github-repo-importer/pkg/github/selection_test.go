@@ -0,0 +1,233 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v67/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepoSelectorSkipReason(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	pushedWithin := 30 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		filters  RepoFilters
+		isPublic *bool
+		repo     *github.Repository
+		expected string
+	}{
+		{
+			name:     "no filters selects active repositories",
+			repo:     &github.Repository{Name: github.String("api")},
+			expected: "",
+		},
+		{
+			name:     "archived repositories are skipped by default",
+			repo:     &github.Repository{Name: github.String("api"), Archived: github.Bool(true)},
+			expected: "archived",
+		},
+		{
+			name:     "archived repositories can be included",
+			filters:  RepoFilters{IncludeArchived: github.Bool(true)},
+			repo:     &github.Repository{Name: github.String("api"), Archived: github.Bool(true)},
+			expected: "",
+		},
+		{
+			name:     "forks can be excluded",
+			filters:  RepoFilters{IncludeForks: github.Bool(false)},
+			repo:     &github.Repository{Name: github.String("api"), Fork: github.Bool(true)},
+			expected: "fork",
+		},
+		{
+			name:     "is_public keeps public repositories only",
+			isPublic: github.Bool(true),
+			repo:     &github.Repository{Name: github.String("api"), Visibility: github.String("internal")},
+			expected: "visibility internal",
+		},
+		{
+			name:     "is_public false keeps private and internal repositories",
+			isPublic: github.Bool(false),
+			repo:     &github.Repository{Name: github.String("api"), Private: github.Bool(true)},
+			expected: "",
+		},
+		{
+			name:     "name glob",
+			filters:  RepoFilters{NamePatterns: []string{"terraform-*"}},
+			repo:     &github.Repository{Name: github.String("api")},
+			expected: "name does not match name_patterns",
+		},
+		{
+			name:     "name regex",
+			filters:  RepoFilters{NameRegex: github.String("^(api|web)-")},
+			repo:     &github.Repository{Name: github.String("web-frontend")},
+			expected: "",
+		},
+		{
+			name:     "topics",
+			filters:  RepoFilters{Topics: []string{"terraform", "go"}},
+			repo:     &github.Repository{Name: github.String("api"), Topics: []string{"python"}},
+			expected: "no matching topic",
+		},
+		{
+			name:     "custom property multi select value",
+			filters:  RepoFilters{CustomProperties: map[string]string{"team": "platform"}},
+			repo:     &github.Repository{Name: github.String("web"), FullName: github.String("acme/web")},
+			expected: "",
+		},
+		{
+			name:     "custom property mismatch",
+			filters:  RepoFilters{CustomProperties: map[string]string{"tier": "critical"}},
+			repo:     &github.Repository{Name: github.String("api"), FullName: github.String("acme/api")},
+			expected: `custom property tier is not "critical"`,
+		},
+		{
+			name:     "last pushed age",
+			filters:  RepoFilters{PushedWithin: &pushedWithin},
+			repo:     &github.Repository{Name: github.String("api"), PushedAt: &github.Timestamp{Time: now.Add(-60 * 24 * time.Hour)}},
+			expected: "not pushed to within 720h0m0s",
+		},
+	}
+
+	importer, _, _ := newFakeOrgImporter(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := importer.newRepoSelector(OrganizationConfig{Name: "acme", IsPublic: tt.isPublic, Filters: &tt.filters})
+			require.NoError(t, err)
+			selector.now = now
+
+			assert.Equal(t, tt.expected, selector.skipReason(tt.repo))
+		})
+	}
+}
+
+func TestRepoFiltersValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		filters   RepoFilters
+		wantError bool
+	}{
+		{name: "valid", filters: RepoFilters{Visibility: []string{"internal"}, NamePatterns: []string{"api-*"}, NameRegex: github.String("^api")}},
+		{name: "unknown visibility", filters: RepoFilters{Visibility: []string{"secret"}}, wantError: true},
+		{name: "broken glob", filters: RepoFilters{NamePatterns: []string{"[api"}}, wantError: true},
+		{name: "broken regex", filters: RepoFilters{NameRegex: github.String("(api")}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filters.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfigValidateVisibility(t *testing.T) {
+	visibility := &RepoFilters{Visibility: []string{VisibilityPrivate}}
+
+	tests := []struct {
+		name      string
+		config    Config
+		wantError bool
+	}{
+		{name: "is_public alone", config: Config{IsPublic: github.Bool(false)}},
+		{name: "visibility alone", config: Config{Filters: visibility}},
+		{name: "is_public and visibility", config: Config{IsPublic: github.Bool(true), Filters: visibility}, wantError: true},
+		{name: "is_public false and visibility", config: Config{IsPublic: github.Bool(false), Filters: visibility}, wantError: true},
+		{
+			name:      "organization visibility next to top level is_public",
+			config:    Config{IsPublic: github.Bool(true), Organizations: []OrganizationConfig{{Name: "acme", Filters: visibility}}},
+			wantError: true,
+		},
+		{
+			name:   "organization is_public overriding top level filters without visibility",
+			config: Config{Filters: &RepoFilters{Topics: []string{"go"}}, Organizations: []OrganizationConfig{{Name: "acme", IsPublic: github.Bool(true)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfigResolveOrganizations(t *testing.T) {
+	filters := &RepoFilters{Topics: []string{"terraform"}}
+	cfg := Config{
+		IsPublic:     github.Bool(true),
+		IgnoredRepos: []string{"labs/legacy", "other/archive"},
+		Filters:      filters,
+		Organizations: []OrganizationConfig{
+			{Name: "acme", SelectedRepos: []string{"api", "acme/web"}},
+			{Name: "labs", IsPublic: github.Bool(false), IgnoredRepos: []string{"sandbox"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		owner     string
+		config    Config
+		expected  []OrganizationConfig
+		wantError bool
+	}{
+		{
+			name:   "all configured organizations",
+			config: cfg,
+			expected: []OrganizationConfig{
+				{Name: "acme", IsPublic: github.Bool(true), SelectedRepos: []string{"acme/api", "acme/web"}, Filters: filters},
+				{Name: "labs", IsPublic: github.Bool(false), IgnoredRepos: []string{"labs/sandbox", "labs/legacy"}, Filters: filters},
+			},
+		},
+		{
+			name:   "owner picks its configured organization",
+			owner:  "Labs",
+			config: cfg,
+			expected: []OrganizationConfig{
+				{Name: "labs", IsPublic: github.Bool(false), IgnoredRepos: []string{"labs/sandbox", "labs/legacy"}, Filters: filters},
+			},
+		},
+		{
+			name:      "top level repositories conflicting with the lists of an organization",
+			config:    Config{IgnoredRepos: []string{"acme/legacy"}, Organizations: []OrganizationConfig{{Name: "acme", SelectedRepos: []string{"api"}}}},
+			wantError: true,
+		},
+		{
+			name:   "owner without entry uses the top level lists",
+			owner:  "acme",
+			config: Config{IgnoredRepos: []string{"acme/legacy"}},
+			expected: []OrganizationConfig{
+				{Name: "acme", IgnoredRepos: []string{"acme/legacy"}},
+			},
+		},
+		{
+			name:      "nothing to import",
+			config:    Config{},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orgs, err := tt.config.ResolveOrganizations(tt.owner)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, orgs)
+		})
+	}
+}
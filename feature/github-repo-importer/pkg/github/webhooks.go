@@ -0,0 +1,90 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v67/github"
+
+	"github.com/gr-oss-devops/github-repo-importer/pkg/file"
+)
+
+const redactedValue = "********"
+
+type Webhook struct {
+	URL         string   `yaml:"url"`
+	ContentType *string  `yaml:"content_type,omitempty"`
+	Events      []string `yaml:"events,omitempty"`
+	Active      *bool    `yaml:"active,omitempty"`
+	InsecureSSL *bool    `yaml:"insecure_ssl,omitempty"`
+}
+
+func CollectWebhooks(client *github.Client, owner, repo string, dumpManager *file.DumpManager) ([]Webhook, error) {
+	var webhooks []Webhook
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		hooks, resp, err := client.Repositories.ListHooks(context.Background(), owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list hooks: %w", err)
+		}
+
+		redactHookSecrets(hooks)
+
+		filename := fmt.Sprintf("hooks-page_%d.json", opts.Page+1)
+		if err := dumpManager.WriteJSONFile(filename, hooks); err != nil {
+			fmt.Printf("failed to write %q: %v\n", filename, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		webhooks = append(webhooks, resolveWebhooks(hooks)...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	return webhooks, nil
+}
+
+// redactHookSecrets masks hook secrets before they are dumped or converted.
+// GitHub already obfuscates them, but we do not rely on that.
+func redactHookSecrets(hooks []*github.Hook) {
+	for _, hook := range hooks {
+		if hook == nil || hook.Config == nil || hook.Config.Secret == nil {
+			continue
+		}
+		hook.Config.Secret = github.String(redactedValue)
+	}
+}
+
+func resolveWebhooks(hooks []*github.Hook) []Webhook {
+	var webhooks []Webhook
+	for _, hook := range hooks {
+		if hook == nil || hook.Config == nil {
+			continue
+		}
+
+		var insecureSSL *bool
+		if hook.Config.InsecureSSL != nil {
+			v := hook.Config.GetInsecureSSL() == "1"
+			insecureSSL = &v
+		}
+
+		webhooks = append(webhooks, Webhook{
+			URL:         hook.Config.GetURL(),
+			ContentType: hook.Config.ContentType,
+			Events:      hook.Events,
+			Active:      hook.Active,
+			InsecureSSL: insecureSSL,
+		})
+	}
+	return webhooks
+}